@@ -18,10 +18,38 @@ var headers = flag.String("headers", "", "HTTP headers for put object, input for
 var force = flag.String("force", "FALSE", "if true, ignore interactive command, never prompt")
 var replace = flag.String("replace", "FALSE", "replace the localfile or object if it is true")
 var suffix = flag.String("suffix", "", "upload file suffix filter")
+var sse = flag.String("sse", "", "server-side encryption for written objects: AES256, KMS or SM4")
+var sse_key_id = flag.String("sse_key_id", "", "KMS key id, only used with --sse=KMS")
 
 var marker = flag.String("marker", "", "get bucket(list objects) parameter")
 var delimiter = flag.String("delimiter", "", "get bucket(list objects) parameter")
 var maxkeys = flag.String("maxkeys", "", "get bucket(list objects) parameter")
+var name = flag.String("name", "", "find: glob pattern matched against the object base name")
+var regex = flag.String("regex", "", "find: regular expression matched against the full object key")
+var larger = flag.String("larger", "", "find: objects larger than size, e.g. 500M, 1G")
+var smaller = flag.String("smaller", "", "find: objects smaller than size, e.g. 10K")
+var older = flag.String("older", "", "find: objects modified before, e.g. 90d, 12h, 2006-01-02")
+var newer = flag.String("newer", "", "find: objects modified after, e.g. 7d, 30m, 2006-01-02")
+var exec = flag.String("exec", "", "find: action for matched objects: delete, copy or restore")
+var flatten = flag.String("flatten", "FALSE", "copybucket: copy objects to target prefix by base name only if it is true")
+var dry_run = flag.String("dry-run", "FALSE", "uploadfromdir/downloadtodir/copybucket/mv/deleteallobject/sync/rm/batch: print the plan without executing it if it is true")
+var include = flag.String("include", "", "uploadfromdir/copybucket/deleteallobject/sync: comma-separated glob patterns to include")
+var exclude = flag.String("exclude", "", "uploadfromdir/copybucket/deleteallobject/sync: comma-separated glob patterns to exclude")
+var include_regex = flag.String("include-regex", "", "include relative paths matching the regular expression")
+var exclude_regex = flag.String("exclude-regex", "", "exclude relative paths matching the regular expression")
+var exclude_from = flag.String("exclude-from", "", "read include(+ glob)/exclude(- glob) rules from file")
+var ignore_file = flag.String("ignore-file", "", "rules file in exclude-from format, default localdir/.ossignore")
+var sync_delete = flag.String("delete", "FALSE", "sync: delete extraneous objects or files in the target if it is true")
+var compare = flag.String("compare", "mtime", "sync/downloadtodir: compare by size, mtime, etag or checksum")
+var depth = flag.Int("depth", -1, "directory depth for du(default 1) and tree(default unlimited)")
+var all_versions = flag.String("all-versions", "FALSE", "list all object versions and delete markers if it is true")
+var version_id = flag.String("version-id", "", "object version id for get/rm")
+var from_file = flag.String("from-file", "", "rm: file with one key per line to delete, - for stdin")
+var failed_file = flag.String("failed-file", "", "rm --from-file: file to write failed keys, default from-file.failed")
+
+var acl = flag.String("acl", "", "bucket acl: private, public-read or public-read-write")
+var storage_class = flag.String("storage_class", "", "bucket storage class: Standard, IA, Archive or ColdArchive")
+var redundancy = flag.String("redundancy", "", "bucket data redundancy type: LRS or ZRS")
 
 var partsize = flag.Int("partsize", 10, "part file upload size")
 var thread_num = flag.Int("thread_num", 10, "object group upload thread num")
@@ -29,27 +57,65 @@ var thread_num = flag.Int("thread_num", 10, "object group upload thread num")
 var VERSION = "0.0.1"
 
 const HELP = `
-    ls(list)        oss://bucket/[prefix] --marker=xxx --delimiter=xxx --maxkeys=xxx
-    uploadfromdir   localdir oss://bucket/[prefix] --replace=false --suffix=".mp3,.mp4"
-    copy            oss://source_bucket/source_object oss://target_bucket/target_object --headers="key1:value1,key2:value2"
-    copybucket      oss://source_bucket/[prefix] oss://target_bucket/[prefix] --replace=false --headers="key1:value1,key2:value2"
-    copylargefile   oss://source_bucket/source_object oss://target_bucket/source_object --headers="key1:value1,key2:value2"
+    lsb             [prefix] --marker=xxx --maxkeys=xxx
+    mb              oss://bucket --acl=private --storage_class=Standard --redundancy=LRS
+    rb              oss://bucket
+    bucketinfo      oss://bucket
+    lifecycle       get|put|rm oss://bucket [rules.json|rules.xml]
+    cors            get|put|rm oss://bucket [cors.json|cors.xml]
+    referer         get|put|rm oss://bucket [referer.json|referer.xml]
+    website         get|put|rm oss://bucket [website.json|website.xml]
+    policy          get|put|rm oss://bucket [policy.json]
+    bucketacl       get|put oss://bucket [private|public-read|public-read-write]
+    replication     get|put|rm|progress oss://bucket [replication.json|rule_id]
+    inventory       ls|get|put|rm oss://bucket [inventory_id|inventory.json]
+    bucketconfig    export oss://bucket > cfg.json
+    bucketconfig    import oss://bucket [cfg.json] < cfg.json
+
+    ls(list)        oss://bucket/[prefix] --marker=xxx --delimiter=xxx --maxkeys=xxx --all-versions=false
+    uploadfromdir   localdir oss://bucket/[prefix] --replace=false --suffix=".mp3,.mp4" --sse=AES256 --dry-run=false
+    downloadtodir   oss://bucket/[prefix] localdir --replace=false --compare=mtime|size|etag|checksum --dry-run=false
+    sync            localdir oss://bucket/[prefix] --delete=false --compare=mtime|size|etag|checksum --force=false --dry-run=false
+    sync            oss://bucket/[prefix] localdir --delete=false --compare=mtime|size|etag|checksum --force=false --dry-run=false
+    copy            oss://source_bucket/source_object oss://target_bucket/target_object --headers="key1:value1,key2:value2" --sse=AES256
+    copybucket      oss://source_bucket/[prefix] oss://target_bucket/[prefix] --replace=false --flatten=false --headers="key1:value1,key2:value2" --sse=AES256 --dry-run=false
+    copylargefile   oss://source_bucket/source_object oss://target_bucket/source_object --headers="key1:value1,key2:value2" --sse=KMS --sse_key_id=xxx
+    mv              oss://source_bucket/source_object oss://target_bucket/[target_object] --sse=AES256 --dry-run=false
+    mv              oss://source_bucket/[prefix/] oss://target_bucket/[prefix] --thread_num=10 --dry-run=false
 
-    get             oss://bucket/object localfile
+    get             oss://bucket/object localfile --version-id=xxx
     cat             oss://bucket/object
     meta            oss://bucket/object
-    rm(delete,del)  oss://bucket/object
+    rm(delete,del)  oss://bucket/object --version-id=xxx
+    rm(delete,del)  oss://bucket --from-file=keys.txt|- --failed-file=keys.txt.failed --thread_num=10 --force=false --dry-run=false
+    ln              oss://bucket/target oss://bucket/symlink
+
+    listallobject   oss://bucket/[prefix] --thread_num=10
+    du              oss://bucket/[prefix] --depth=1
+    tree            oss://bucket/[prefix] --depth=N
+    find            oss://bucket/[prefix] [oss://target_bucket/[prefix]] --name="*.log" --regex=xxx --larger=1G --smaller=10K --older=90d --newer=2006-01-02 --storage-class=IA --exec=delete|copy|restore
+    deleteallobject oss://bucket/[prefix] --force=false --dry-run=false
+
+    put             localfile oss://bucket/object --headers="key1:value1,key2:value2" --sse=AES256
+    upload          localfile oss://bucket/object --headers="key1:value1,key2:value2" --sse=AES256
+    uploadlargefile localfile oss://bucket/object --headers="key1:value1,key2:value2" --sse=KMS --sse_key_id=xxx
+    append          localfile oss://bucket/object --partsize=10
 
-    listallobject   oss://bucket/[prefix]
-    deleteallobject oss://bucket/[prefix] --force=false
+    batch           manifest.jsonl|manifest.csv|- [result.jsonl] --thread_num=10 --sse=AES256 --dry-run=false
+                    manifest line: {"src":"oss://bucket/a","dst":"oss://bucket/b","headers":{"x-oss-meta-k":"v"},"storage_class":"IA","tags":{"k":"v"}}
+                    src/dst may be a local path for upload/download, csv columns: src,dst,storage_class,headers,tags
+
+    filters for uploadfromdir/downloadtodir/copybucket/mv/deleteallobject/sync:
+                    --include="*.jpg,img/" --exclude="*.log,tmp/" --include-regex=xxx --exclude-regex=xxx
+                    --exclude-from=rules.txt --ignore-file=localdir/.ossignore
+                    rules file lines: "+ glob" include, "- glob" or "glob" exclude, first matching rule wins
 
-    put             localfile oss://bucket/object --headers="key1:value1,key2:value2"
-    upload          localfile oss://bucket/object --headers="key1:value1,key2:value2"
-    uploadlargefile localfile oss://bucket/object --headers="key1:value1,key2:value2"
     config --host=oss.aliyuncs.com --id=accessid --key=accesskey
 `
 
 func main() {
+	//--storage-class与--storage_class等价
+	flag.StringVar(storage_class, "storage-class", "", "same as --storage_class")
 	flag.Parse()
 	args := flag.Args()
 
@@ -80,20 +146,49 @@ func main() {
 
 	//options参数
 	options := map[string]string{
-		"headers":    *headers,
-		"force":      *force,
-		"replace":    *replace,
-		"suffix":     *suffix,
-		"marker":     *marker,
-		"delimiter":  *delimiter,
-		"maxkeys":    *maxkeys,
-		"partsize":   strconv.Itoa(*partsize * 1024 * 1024),
-		"thread_num": strconv.Itoa(*thread_num),
+		"headers":       *headers,
+		"force":         *force,
+		"replace":       *replace,
+		"suffix":        *suffix,
+		"sse":           *sse,
+		"sse_key_id":    *sse_key_id,
+		"marker":        *marker,
+		"delimiter":     *delimiter,
+		"maxkeys":       *maxkeys,
+		"dry-run":       *dry_run,
+		"flatten":       *flatten,
+		"include":       *include,
+		"exclude":       *exclude,
+		"include-regex": *include_regex,
+		"exclude-regex": *exclude_regex,
+		"exclude-from":  *exclude_from,
+		"ignore-file":   *ignore_file,
+		"delete":        *sync_delete,
+		"compare":       *compare,
+		"depth":         strconv.Itoa(*depth),
+		"name":          *name,
+		"regex":         *regex,
+		"larger":        *larger,
+		"smaller":       *smaller,
+		"older":         *older,
+		"newer":         *newer,
+		"exec":          *exec,
+		"all-versions":  *all_versions,
+		"version-id":    *version_id,
+		"from-file":     *from_file,
+		"failed-file":   *failed_file,
+		"acl":           *acl,
+		"storage_class": *storage_class,
+		"redundancy":    *redundancy,
+		"partsize":      strconv.Itoa(*partsize * 1024 * 1024),
+		"thread_num":    strconv.Itoa(*thread_num),
 	}
 
 	switch args[0] {
 	case "uploadlargefile":
 		osscmd.UploadLargeFile(args, options)
+	case "append":
+		osscmd.Append(args, options)
 	case "upload":
 		fallthrough
 	case "put":
@@ -104,12 +199,52 @@ func main() {
 		osscmd.CopyBigObject(args, options)
 	case "copybucket":
 		osscmd.CopyBucket(args, options)
+	case "mv":
+		osscmd.Move(args, options)
+	case "batch":
+		osscmd.Batch(args, options)
+	case "downloadtodir":
+		osscmd.DownloadToDir(args, options)
+	case "sync":
+		osscmd.Sync(args, options)
 	case "uploadfromdir":
 		osscmd.UploadFromDir(args, options)
 	case "deleteallobject":
 		osscmd.DeleteAllObject(args, options)
 	case "listallobject":
 		osscmd.ListAllObject(args, options)
+	case "du":
+		osscmd.DiskUsage(args, options)
+	case "tree":
+		osscmd.Tree(args, options)
+	case "find":
+		osscmd.Find(args, options)
+	case "lsb":
+		osscmd.ListBuckets(args, options)
+	case "mb":
+		osscmd.CreateBucket(args, options)
+	case "rb":
+		osscmd.DeleteBucket(args)
+	case "bucketinfo":
+		osscmd.BucketInfo(args)
+	case "lifecycle":
+		osscmd.Lifecycle(args)
+	case "cors":
+		osscmd.CORS(args)
+	case "referer":
+		osscmd.Referer(args)
+	case "website":
+		osscmd.Website(args)
+	case "policy":
+		osscmd.Policy(args)
+	case "bucketacl":
+		osscmd.BucketACL(args, options)
+	case "replication":
+		osscmd.Replication(args)
+	case "inventory":
+		osscmd.Inventory(args)
+	case "bucketconfig":
+		osscmd.BucketConfigCommand(args)
 	case "list":
 		fallthrough
 	case "ls":
@@ -119,11 +254,13 @@ func main() {
 	case "del":
 		fallthrough
 	case "rm":
-		osscmd.Delete(args)
+		osscmd.Delete(args, options)
+	case "ln":
+		osscmd.Symlink(args, options)
 	case "cat":
 		osscmd.Cat(args)
 	case "get":
-		osscmd.Get(args)
+		osscmd.Get(args, options)
 	case "meta":
 		osscmd.Head(args)
 	case "help":
@@ -134,6 +271,10 @@ func main() {
 		fmt.Println("use --help for more information")
 		os.Exit(0)
 	}
+	//输出配置文档的命令不追加耗时，便于重定向到文件
+	if len(args) > 1 && (args[1] == "get" || args[1] == "export") {
+		return
+	}
 	fmt.Printf("%.3f(s) elapsed\n", time.Now().Sub(begin).Seconds())
 }
 
@@ -144,8 +285,12 @@ func parse_args(args []string) []string {
 			res = append(res, args[k])
 			continue
 		}
-		tmp := strings.Split(v, "=")
-		name, value := strings.TrimLeft(tmp[0], "--"), tmp[1]
+		//--name不带值时视为true
+		tmp := strings.SplitN(v, "=", 2)
+		name, value := strings.TrimLeft(tmp[0], "--"), "true"
+		if len(tmp) > 1 {
+			value = tmp[1]
+		}
 		if flag.Lookup(name) != nil {
 			flag.Set(name, value)
 		}
@@ -2,11 +2,16 @@ package osscmd
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"github.com/Unknwon/goconfig"
+	"io"
 	"io/ioutil"
 	"lib/aliyun/oss"
 	"os"
+	"path"
 	"strconv"
 	"strings"
 	"time"
@@ -59,7 +64,11 @@ func Upload(args []string, options map[string]string) {
 	bucket, object := parse_bucket_object(args[2])
 	headers := parse_headers(options["headers"])
 
-	tmp, err := client.UploadFile(srcFile, bucket, object, map[string]string{"disposition": headers["disposition"]})
+	tmp, err := client.UploadFile(srcFile, bucket, object, map[string]string{
+		"disposition": headers["disposition"],
+		"sse":         options["sse"],
+		"sse-key-id":  options["sse_key_id"],
+	})
 	if err != nil {
 		fmt.Println("upload::", err)
 		os.Exit(2)
@@ -83,6 +92,8 @@ func UploadLargeFile(args []string, options map[string]string) {
 		"disposition": headers["disposition"],
 		"partsize":    options["partsize"],
 		"thread_num":  options["thread_num"],
+		"sse":         options["sse"],
+		"sse-key-id":  options["sse_key_id"],
 	})
 	if err != nil {
 		fmt.Println("uploadlarge::", err)
@@ -94,6 +105,57 @@ func UploadLargeFile(args []string, options map[string]string) {
 	fmt.Println(res)
 }
 
+func Append(args []string, options map[string]string) {
+	if len(args) < 3 {
+		fmt.Println("append miss parameters")
+		os.Exit(0)
+	}
+	srcFile := args[1]
+	bucket, object := parse_bucket_object(args[2])
+	if object == "" || strings.HasSuffix(object, "/") {
+		object = strings.TrimRight(object, "/") + "/" + path.Base(srcFile)
+		object = strings.TrimLeft(object, "/")
+	}
+	fd, err := os.Open(srcFile)
+	if err != nil {
+		fmt.Println("append::", err)
+		os.Exit(2)
+	}
+	defer fd.Close()
+	writer, err := client.NewAppendWriter(bucket, object)
+	if err != nil {
+		fmt.Println("append::", err)
+		os.Exit(2)
+	}
+	partSize, _ := strconv.Atoi(options["partsize"])
+	if partSize <= 0 {
+		partSize = 10 * 1024 * 1024
+	}
+	//按partsize分块追加
+	buf := make([]byte, partSize)
+	for {
+		n, err := io.ReadFull(fd, buf)
+		if n > 0 {
+			if _, err := writer.Write(buf[:n]); err != nil {
+				fmt.Println("append::", err)
+				os.Exit(2)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			fmt.Println("append::", err)
+			os.Exit(2)
+		}
+	}
+	writer.Close()
+	res := "\nObject abstract path is: oss://" + bucket + "/" + object + "\n"
+	res += "Next append position is " + strconv.FormatInt(writer.Position(), 10) + "\n"
+	res += "CRC64 is " + writer.CRC64
+	fmt.Println(res)
+}
+
 func CopyBucket(args []string, options map[string]string) {
 	if len(args) < 3 {
 		fmt.Println("copybucket miss parameters")
@@ -102,11 +164,24 @@ func CopyBucket(args []string, options map[string]string) {
 	sourceBucket, sourceObject := parse_bucket_object(args[1])
 	sourceFullObject := "/" + sourceBucket + "/" + sourceObject
 	bucket, object := parse_bucket_object(args[2])
-
-	tmp, err := client.CopyAllObject(bucket, object, sourceFullObject, map[string]string{
+	copyOptions := filter_options(map[string]string{
+		"flatten":    options["flatten"],
 		"replace":    options["replace"],
 		"thread_num": options["thread_num"],
-	})
+		"sse":        options["sse"],
+		"sse-key-id": options["sse_key_id"],
+	}, options)
+	if options["dry-run"] == "true" {
+		plan, err := client.CopyAllObjectPlan(bucket, object, sourceFullObject, copyOptions)
+		if err != nil {
+			fmt.Println("copybucket::", err)
+			os.Exit(2)
+		}
+		print_plan(plan)
+		return
+	}
+
+	tmp, err := client.CopyAllObject(bucket, object, sourceFullObject, copyOptions)
 	if err != nil {
 		fmt.Println("copybucket::", err)
 		os.Exit(2)
@@ -136,6 +211,8 @@ func CopyBigObject(args []string, options map[string]string) {
 		"replace":     options["replace"],
 		"partsize":    options["partsize"],
 		"thread_num":  options["thread_num"],
+		"sse":         options["sse"],
+		"sse-key-id":  options["sse_key_id"],
 	})
 	if err != nil {
 		fmt.Println("copybigobject::", err)
@@ -154,12 +231,24 @@ func UploadFromDir(args []string, options map[string]string) {
 	}
 	srcFile := args[1]
 	bucket, object := parse_bucket_object(args[2])
-
-	tmp, err := client.UploadFromDir(srcFile, bucket, object, map[string]string{
+	uploadOptions := filter_options(map[string]string{
 		"replace":    options["replace"],
 		"suffix":     options["suffix"],
 		"thread_num": options["thread_num"],
-	})
+		"sse":        options["sse"],
+		"sse-key-id": options["sse_key_id"],
+	}, options)
+	if options["dry-run"] == "true" {
+		plan, err := client.UploadFromDirPlan(srcFile, bucket, object, uploadOptions)
+		if err != nil {
+			fmt.Println("uploadfromdir::", err)
+			os.Exit(2)
+		}
+		print_plan(plan)
+		return
+	}
+
+	tmp, err := client.UploadFromDir(srcFile, bucket, object, uploadOptions)
 	if err != nil {
 		fmt.Println("uploadfromdir::", err)
 		os.Exit(2)
@@ -172,13 +261,56 @@ func UploadFromDir(args []string, options map[string]string) {
 	fmt.Println(res)
 }
 
-func Delete(args []string) {
+func DownloadToDir(args []string, options map[string]string) {
+	if len(args) < 3 {
+		fmt.Println("downloadtodir miss parameters")
+		os.Exit(0)
+	}
+	bucket, prefix := parse_bucket_object(args[1])
+	localdir := args[2]
+	downloadOptions := filter_options(map[string]string{
+		"replace":    options["replace"],
+		"compare":    options["compare"],
+		"thread_num": options["thread_num"],
+	}, options)
+	plan, err := client.DownloadToDirPlan(bucket, prefix, localdir, downloadOptions)
+	if err != nil {
+		fmt.Println("downloadtodir::", err)
+		os.Exit(2)
+	}
+	if options["dry-run"] == "true" {
+		print_plan(plan)
+		return
+	}
+	for _, action := range plan.Actions {
+		if action.Reason == "unsafe key" {
+			fmt.Println("downloadtodir::skip unsafe key: oss://" + bucket + "/" + action.Key)
+		}
+	}
+	tmp, err := client.ExecutePlan(plan, downloadOptions)
+	if err != nil {
+		fmt.Println("downloadtodir::", err)
+		os.Exit(2)
+	}
+	finish := strconv.Itoa(tmp["finish"])
+	skip := strconv.Itoa(tmp["skip"])
+	fail := strconv.Itoa(tmp["total"] - tmp["finish"] - tmp["skip"])
+	res := "\nTotal being downloaded objects num: " + strconv.Itoa(tmp["total"]) + "\n"
+	res += "OK num:" + finish + ", SKIP num:" + skip + ", FAIL num:" + fail + "\n"
+	fmt.Println(res)
+}
+
+func Delete(args []string, options map[string]string) {
 	if len(args) < 2 {
 		fmt.Println("delete miss parameters")
 		os.Exit(0)
 	}
+	if options["from-file"] != "" {
+		DeleteFromFile(args, options)
+		return
+	}
 	bucket, object := parse_bucket_object(args[1])
-	tmp, err := client.Delete(bucket, object)
+	tmp, err := client.Delete(bucket, object, map[string]string{"versionId": options["version-id"]})
 	if err != nil {
 		fmt.Println("delete::", err)
 		os.Exit(2)
@@ -194,6 +326,16 @@ func DeleteAllObject(args []string, options map[string]string) {
 		fmt.Println("deleteallobject miss parameters")
 		os.Exit(0)
 	}
+	if options["dry-run"] == "true" {
+		bucket, object := parse_bucket_object(args[1])
+		plan, err := client.DeleteAllObjectPlan(bucket, object, filter_options(map[string]string{}, options))
+		if err != nil {
+			fmt.Println("deleteallobject::", err)
+			os.Exit(2)
+		}
+		print_plan(plan)
+		return
+	}
 	if options["force"] != "true" {
 		fmt.Println("DELETE all objects? y/N, default is N: ")
 		reader := bufio.NewReader(os.Stdin)
@@ -204,9 +346,9 @@ func DeleteAllObject(args []string, options map[string]string) {
 		}
 	}
 	bucket, object := parse_bucket_object(args[1])
-	tmp, err := client.DeleteAllObject(bucket, object, map[string]string{
+	tmp, err := client.DeleteAllObject(bucket, object, filter_options(map[string]string{
 		"thread_num": options["thread_num"],
-	})
+	}, options))
 	if err != nil {
 		fmt.Println("deleteallobject::", err)
 		os.Exit(2)
@@ -227,21 +369,10 @@ func ListAllObject(args []string, options map[string]string) {
 	totalNum := 0
 	totalSize := 0
 	bucket, prefix := parse_bucket_object(args[1])
-	marker := ""
-	maxkeys := 1000
-LIST:
-	list, err := client.ListObject(bucket, map[string]string{
-		"marker":   marker,
-		"prefix":   prefix,
-		"max-keys": strconv.Itoa(maxkeys),
-	})
-	if err != nil {
-		fmt.Println("list::", err)
-		os.Exit(2)
-	}
-	totalNum += len(list.Contents)
-	for _, v := range list.Contents {
-		marker = v.Key
+	threadNum, _ := strconv.Atoi(options["thread_num"])
+	list, listErr := client.ListParallel(bucket, prefix, threadNum, nil)
+	for v := range list {
+		totalNum++
 		lastModified, _ := time.Parse("2006-01-02T15:04:05.000Z", v.LastModified)
 		tmpDatetime := time.Unix(lastModified.Unix(), 0).Format(dateTimeFormat)
 		tmpSize, _ := strconv.Atoi(v.Size)
@@ -249,8 +380,9 @@ LIST:
 		content := tmpDatetime + " " + size_format(tmpSize) + " " + "oss://" + bucket + "/" + v.Key
 		fmt.Println(content)
 	}
-	if list.IsTruncated == "true" {
-		goto LIST
+	if err := <-listErr; err != nil {
+		fmt.Println("list::", err)
+		os.Exit(2)
 	}
 	end := fmt.Sprintf("object list number is: %d\n", totalNum)
 	end += fmt.Sprintf("totalsize is: real:%d, format:%s\n", totalSize, size_format(totalSize))
@@ -277,9 +409,12 @@ func ListObject(args []string, options map[string]string) {
 	if options["maxkeys"] != "" {
 		maxkeys, _ = strconv.Atoi(options["maxkeys"])
 	}
-	content := "prefix list is: \n"
-	content += "object list is:"
-	fmt.Println(content)
+	if options["all-versions"] == "true" {
+		ListObjectVersions(bucket, prefix, delimiter, maxkeys)
+		return
+	}
+	prefixList := make([]string, 0)
+	objectList := make([]string, 0)
 LIST:
 	list, err := client.ListObject(bucket, map[string]string{
 		"marker":    marker,
@@ -291,23 +426,113 @@ LIST:
 		fmt.Println("list::", err)
 		os.Exit(2)
 	}
-	total += len(list.Contents)
+	total += len(list.Contents) + len(list.CommonPrefixes)
+	for _, v := range list.CommonPrefixes {
+		prefixList = append(prefixList, "oss://"+bucket+"/"+v.Prefix)
+	}
 	for _, v := range list.Contents {
 		marker = v.Key
 		lastModified, _ := time.Parse("2006-01-02T15:04:05.000Z", v.LastModified)
 		tmpDatetime := time.Unix(lastModified.Unix(), 0).Format(dateTimeFormat)
 		tmpSize, _ := strconv.Atoi(v.Size)
 		content := tmpDatetime + " " + size_format(tmpSize) + " " + v.StorageClass + " " + "oss://" + bucket + "/" + v.Key
+		if v.Type == "Symlink" {
+			content += " -> " + symlink_target(bucket, v.Key)
+		}
+		objectList = append(objectList, content)
+	}
+	//有delimiter时最后一项可能是目录，以NextMarker为准
+	if list.NextMarker != "" {
+		marker = list.NextMarker
+	}
+	if maxkeys > total && list.IsTruncated == "true" {
+		goto LIST
+	}
+	fmt.Println("prefix list is: ")
+	for _, v := range prefixList {
+		fmt.Println(v)
+	}
+	fmt.Println("object list is:")
+	for _, v := range objectList {
+		fmt.Println(v)
+	}
+	end := "\nprefix list number is: " + strconv.Itoa(len(prefixList)) + " \n"
+	end += "object list number is: " + strconv.Itoa(len(objectList))
+	fmt.Println(end)
+}
+
+func ListObjectVersions(bucket, prefix, delimiter string, maxkeys int) {
+	total := 0
+	keyMarker := ""
+	versionIdMarker := ""
+	fmt.Println("object version list is:")
+LIST:
+	list, err := client.ListObjectVersions(bucket, map[string]string{
+		"key-marker":        keyMarker,
+		"version-id-marker": versionIdMarker,
+		"prefix":            prefix,
+		"delimiter":         delimiter,
+		"max-keys":          strconv.Itoa(maxkeys),
+	})
+	if err != nil {
+		fmt.Println("list::", err)
+		os.Exit(2)
+	}
+	total += len(list.Versions) + len(list.DeleteMarkers)
+	for _, v := range list.Versions {
+		lastModified, _ := time.Parse("2006-01-02T15:04:05.000Z", v.LastModified)
+		tmpDatetime := time.Unix(lastModified.Unix(), 0).Format(dateTimeFormat)
+		tmpSize, _ := strconv.Atoi(v.Size)
+		content := tmpDatetime + " " + size_format(tmpSize) + " " + v.StorageClass + " " + v.VersionId + " " + "oss://" + bucket + "/" + v.Key
+		if v.IsLatest == "true" {
+			content += " (latest)"
+		}
 		fmt.Println(content)
 	}
-	if maxkeys != total && list.IsTruncated == "true" {
+	for _, v := range list.DeleteMarkers {
+		lastModified, _ := time.Parse("2006-01-02T15:04:05.000Z", v.LastModified)
+		tmpDatetime := time.Unix(lastModified.Unix(), 0).Format(dateTimeFormat)
+		content := tmpDatetime + " DeleteMarker " + v.VersionId + " " + "oss://" + bucket + "/" + v.Key
+		if v.IsLatest == "true" {
+			content += " (latest)"
+		}
+		fmt.Println(content)
+	}
+	if total < maxkeys && list.IsTruncated == "true" {
+		keyMarker = list.NextKeyMarker
+		versionIdMarker = list.NextVersionIdMarker
 		goto LIST
 	}
-	end := "\nprefix list number is: 0 \n"
-	end += "object list number is: " + strconv.Itoa(total)
+	end := "\nobject version list number is: " + strconv.Itoa(total)
 	fmt.Println(end)
 }
 
+func Symlink(args []string, options map[string]string) {
+	if len(args) < 3 {
+		fmt.Println("ln miss parameters")
+		os.Exit(0)
+	}
+	targetBucket, target := parse_bucket_object(args[1])
+	bucket, symlink := parse_bucket_object(args[2])
+	if targetBucket != bucket {
+		fmt.Println("ln::symlink and target SHOULD be in the same bucket")
+		os.Exit(0)
+	}
+	if target == "" || symlink == "" {
+		fmt.Println("ln miss parameters")
+		os.Exit(0)
+	}
+	headers := parse_headers(options["headers"])
+	tmp, err := client.PutSymlink(bucket, symlink, target, map[string]string{"disposition": headers["disposition"]})
+	if err != nil {
+		fmt.Println("ln::", err)
+		os.Exit(2)
+	}
+	res := "\nSymlink abstract path is: oss://" + tmp["Bucket"] + "/" + tmp["Key"] + "\n"
+	res += "Target abstract path is: oss://" + tmp["Bucket"] + "/" + tmp["Target"]
+	fmt.Println(res)
+}
+
 func Cat(args []string) {
 	if len(args) < 2 {
 		fmt.Println("cat miss parameters")
@@ -347,14 +572,14 @@ func Cat(args []string) {
 	}
 }
 
-func Get(args []string) {
+func Get(args []string, options map[string]string) {
 	if len(args) < 3 {
 		fmt.Println("get miss parameters")
 		os.Exit(0)
 	}
 	bucket, object := parse_bucket_object(args[1])
 	localfile := args[2]
-	tmp, err := client.Get(bucket, object, localfile)
+	tmp, err := client.Get(bucket, object, localfile, map[string]string{"versionId": options["version-id"]})
 	if err != nil {
 		fmt.Println("get::", err)
 		os.Exit(2)
@@ -383,6 +608,373 @@ func Head(args []string) {
 	fmt.Println(res)
 }
 
+func ListBuckets(args []string, options map[string]string) {
+	total := 0
+	prefix := ""
+	if len(args) > 1 {
+		prefix = strings.Replace(args[1], "oss://", "", 1)
+	}
+	marker := options["marker"]
+	maxkeys := 1000
+	if options["maxkeys"] != "" {
+		maxkeys, _ = strconv.Atoi(options["maxkeys"])
+	}
+LIST:
+	list, err := client.ListBuckets(map[string]string{
+		"prefix":   prefix,
+		"marker":   marker,
+		"max-keys": strconv.Itoa(maxkeys),
+	})
+	if err != nil {
+		fmt.Println("lsb::", err)
+		os.Exit(2)
+	}
+	total += len(list.Buckets)
+	for _, v := range list.Buckets {
+		creationDate, _ := time.Parse("2006-01-02T15:04:05.000Z", v.CreationDate)
+		tmpDatetime := time.Unix(creationDate.Unix(), 0).Format(dateTimeFormat)
+		content := tmpDatetime + " " + v.Location + " " + v.StorageClass + " " + "oss://" + v.Name
+		fmt.Println(content)
+	}
+	if list.IsTruncated == "true" && list.NextMarker != "" {
+		marker = list.NextMarker
+		goto LIST
+	}
+	end := "\nbucket list number is: " + strconv.Itoa(total)
+	fmt.Println(end)
+}
+
+func CreateBucket(args []string, options map[string]string) {
+	if len(args) < 2 {
+		fmt.Println("mb miss parameters")
+		os.Exit(0)
+	}
+	bucket, _ := parse_bucket_object(args[1])
+	_, err := client.CreateBucket(bucket, map[string]string{
+		"acl":           options["acl"],
+		"storage_class": options["storage_class"],
+		"redundancy":    options["redundancy"],
+	})
+	if err != nil {
+		fmt.Println("mb::", err)
+		os.Exit(2)
+	}
+	fmt.Println("create bucket oss://" + bucket + " OK")
+}
+
+func DeleteBucket(args []string) {
+	if len(args) < 2 {
+		fmt.Println("rb miss parameters")
+		os.Exit(0)
+	}
+	bucket, _ := parse_bucket_object(args[1])
+	_, err := client.DeleteBucket(bucket)
+	if err != nil {
+		fmt.Println("rb::", err)
+		os.Exit(2)
+	}
+	fmt.Println("delete bucket oss://" + bucket + " OK")
+}
+
+func BucketInfo(args []string) {
+	if len(args) < 2 {
+		fmt.Println("bucketinfo miss parameters")
+		os.Exit(0)
+	}
+	bucket, _ := parse_bucket_object(args[1])
+	info, err := client.GetBucketInfo(bucket)
+	if err != nil {
+		fmt.Println("bucketinfo::", err)
+		os.Exit(2)
+	}
+	location, err := client.GetBucketLocation(bucket)
+	if err != nil {
+		fmt.Println("bucketinfo::", err)
+		os.Exit(2)
+	}
+	rows := [][]string{
+		{"name", info.Name},
+		{"location", location},
+		{"creationdate", info.CreationDate},
+		{"extranetendpoint", info.ExtranetEndpoint},
+		{"intranetendpoint", info.IntranetEndpoint},
+		{"storageclass", info.StorageClass},
+		{"redundancy", info.DataRedundancyType},
+		{"acl", info.Grant},
+		{"owner", info.Owner.ID},
+		{"versioning", info.Versioning},
+		{"crossregionreplication", info.CrossRegionReplication},
+		{"transferacceleration", info.TransferAcceleration},
+		{"sse", info.ServerSideEncryptionRule.SSEAlgorithm},
+		{"sse_key_id", info.ServerSideEncryptionRule.KMSMasterKeyID},
+		{"logbucket", info.LogBucket},
+		{"logprefix", info.LogPrefix},
+		{"comment", info.Comment},
+	}
+	res := ""
+	for _, row := range rows {
+		if row[1] != "" {
+			res += fmt.Sprintf("%-22s: %s\n", row[0], row[1])
+		}
+	}
+	fmt.Println(res)
+}
+
+func Lifecycle(args []string) {
+	var lifecycle oss.LifecycleConfiguration
+	bucket_config_command("lifecycle", args, &lifecycle,
+		func(bucket string) (interface{}, error) { return client.GetBucketLifecycle(bucket) },
+		func(bucket string) (map[string]string, error) { return client.PutBucketLifecycle(bucket, &lifecycle) },
+		client.DeleteBucketLifecycle)
+}
+
+func CORS(args []string) {
+	var cors oss.CORSConfiguration
+	bucket_config_command("cors", args, &cors,
+		func(bucket string) (interface{}, error) { return client.GetBucketCORS(bucket) },
+		func(bucket string) (map[string]string, error) { return client.PutBucketCORS(bucket, &cors) },
+		client.DeleteBucketCORS)
+}
+
+func Referer(args []string) {
+	var referer oss.RefererConfiguration
+	bucket_config_command("referer", args, &referer,
+		func(bucket string) (interface{}, error) { return client.GetBucketReferer(bucket) },
+		func(bucket string) (map[string]string, error) { return client.PutBucketReferer(bucket, &referer) },
+		client.DeleteBucketReferer)
+}
+
+func Website(args []string) {
+	var website oss.WebsiteConfiguration
+	bucket_config_command("website", args, &website,
+		func(bucket string) (interface{}, error) { return client.GetBucketWebsite(bucket) },
+		func(bucket string) (map[string]string, error) { return client.PutBucketWebsite(bucket, &website) },
+		client.DeleteBucketWebsite)
+}
+
+func Policy(args []string) {
+	if len(args) < 3 {
+		fmt.Println("policy miss parameters")
+		os.Exit(0)
+	}
+	bucket, _ := parse_bucket_object(args[2])
+	switch args[1] {
+	case "get":
+		policy, err := client.GetBucketPolicy(bucket)
+		if err != nil {
+			fmt.Println("policy::", err)
+			os.Exit(2)
+		}
+		var res bytes.Buffer
+		if err := json.Indent(&res, []byte(policy), "", "  "); err != nil {
+			fmt.Println(policy)
+			return
+		}
+		fmt.Println(res.String())
+	case "put":
+		if len(args) < 4 {
+			fmt.Println("policy put miss policy file")
+			os.Exit(0)
+		}
+		policy, err := ioutil.ReadFile(args[3])
+		if err != nil {
+			fmt.Println("policy::", err)
+			os.Exit(2)
+		}
+		if _, err := client.PutBucketPolicy(bucket, string(policy)); err != nil {
+			fmt.Println("policy::", err)
+			os.Exit(2)
+		}
+		fmt.Println("put policy of oss://" + bucket + " OK")
+	case "rm":
+		if _, err := client.DeleteBucketPolicy(bucket); err != nil {
+			fmt.Println("policy::", err)
+			os.Exit(2)
+		}
+		fmt.Println("delete policy of oss://" + bucket + " OK")
+	default:
+		fmt.Println("unsupported policy command : " + args[1])
+		os.Exit(0)
+	}
+}
+
+func BucketACL(args []string, options map[string]string) {
+	if len(args) < 3 {
+		fmt.Println("bucketacl miss parameters")
+		os.Exit(0)
+	}
+	bucket, _ := parse_bucket_object(args[2])
+	switch args[1] {
+	case "get":
+		acl, err := client.GetBucketACL(bucket)
+		if err != nil {
+			fmt.Println("bucketacl::", err)
+			os.Exit(2)
+		}
+		res := fmt.Sprintf("%-20s: %s\n", "bucket", bucket)
+		res += fmt.Sprintf("%-20s: %s\n", "owner", acl.Owner.ID)
+		res += fmt.Sprintf("%-20s: %s\n", "acl", acl.Grant)
+		fmt.Println(res)
+	case "put":
+		acl := options["acl"]
+		if len(args) > 3 {
+			acl = args[3]
+		}
+		if _, err := client.PutBucketACL(bucket, acl); err != nil {
+			fmt.Println("bucketacl::", err)
+			os.Exit(2)
+		}
+		fmt.Println("put acl " + acl + " of oss://" + bucket + " OK")
+	default:
+		fmt.Println("unsupported bucketacl command : " + args[1])
+		os.Exit(0)
+	}
+}
+
+func Replication(args []string) {
+	if len(args) < 3 {
+		fmt.Println("replication miss parameters")
+		os.Exit(0)
+	}
+	bucket, _ := parse_bucket_object(args[2])
+	param := ""
+	if len(args) > 3 {
+		param = args[3]
+	}
+	switch args[1] {
+	case "get":
+		replication, err := client.GetBucketReplication(bucket)
+		if err != nil {
+			fmt.Println("replication::", err)
+			os.Exit(2)
+		}
+		print_json(replication)
+	case "put":
+		if param == "" {
+			fmt.Println("replication put miss config file")
+			os.Exit(0)
+		}
+		var replication oss.ReplicationConfiguration
+		read_config_file(param, &replication)
+		if _, err := client.PutBucketReplication(bucket, &replication); err != nil {
+			fmt.Println("replication::", err)
+			os.Exit(2)
+		}
+		fmt.Println("put replication of oss://" + bucket + " OK")
+	case "rm":
+		if param == "" {
+			fmt.Println("replication rm miss rule_id")
+			os.Exit(0)
+		}
+		if _, err := client.DeleteBucketReplication(bucket, param); err != nil {
+			fmt.Println("replication::", err)
+			os.Exit(2)
+		}
+		fmt.Println("delete replication rule " + param + " of oss://" + bucket + " OK")
+	case "progress":
+		progress, err := client.GetBucketReplicationProgress(bucket, param)
+		if err != nil {
+			fmt.Println("replication::", err)
+			os.Exit(2)
+		}
+		for _, rule := range progress.Rules {
+			historical := "-"
+			newObject := "-"
+			if rule.Progress != nil {
+				if tmp, err := strconv.ParseFloat(rule.Progress.HistoricalObject, 64); err == nil {
+					historical = fmt.Sprintf("%.0f%%", tmp*100)
+				}
+				if rule.Progress.NewObject != "" {
+					tmp, _ := time.Parse("2006-01-02T15:04:05.000Z", rule.Progress.NewObject)
+					newObject = time.Unix(tmp.Unix(), 0).Format(dateTimeFormat)
+				}
+			}
+			res := fmt.Sprintf("%-20s: %s\n", "rule", rule.ID)
+			res += fmt.Sprintf("%-20s: oss://%s (%s)\n", "destination", rule.Destination.Bucket, rule.Destination.Location)
+			res += fmt.Sprintf("%-20s: %s\n", "status", rule.Status)
+			res += fmt.Sprintf("%-20s: %s\n", "historical", historical)
+			res += fmt.Sprintf("%-20s: %s\n", "newobject", newObject)
+			fmt.Println(res)
+		}
+	default:
+		fmt.Println("unsupported replication command : " + args[1])
+		os.Exit(0)
+	}
+}
+
+func Inventory(args []string) {
+	if len(args) < 3 {
+		fmt.Println("inventory miss parameters")
+		os.Exit(0)
+	}
+	bucket, _ := parse_bucket_object(args[2])
+	param := ""
+	if len(args) > 3 {
+		param = args[3]
+	}
+	switch args[1] {
+	case "ls":
+		token := ""
+		total := 0
+	LIST:
+		list, err := client.ListBucketInventory(bucket, map[string]string{"continuation-token": token})
+		if err != nil {
+			fmt.Println("inventory::", err)
+			os.Exit(2)
+		}
+		total += len(list.Configurations)
+		for _, v := range list.Configurations {
+			enabled := "disabled"
+			if v.IsEnabled {
+				enabled = "enabled"
+			}
+			content := v.Id + " " + enabled + " " + v.Frequency + " " + v.IncludedObjectVersions + " " + v.Destination.Bucket + "/" + v.Destination.Prefix
+			fmt.Println(content)
+		}
+		if list.IsTruncated == "true" && list.NextContinuationToken != "" {
+			token = list.NextContinuationToken
+			goto LIST
+		}
+		fmt.Println("\ninventory list number is: " + strconv.Itoa(total))
+	case "get":
+		if param == "" {
+			fmt.Println("inventory get miss inventory_id")
+			os.Exit(0)
+		}
+		inventory, err := client.GetBucketInventory(bucket, param)
+		if err != nil {
+			fmt.Println("inventory::", err)
+			os.Exit(2)
+		}
+		print_json(inventory)
+	case "put":
+		if param == "" {
+			fmt.Println("inventory put miss config file")
+			os.Exit(0)
+		}
+		var inventory oss.InventoryConfiguration
+		read_config_file(param, &inventory)
+		if _, err := client.PutBucketInventory(bucket, &inventory); err != nil {
+			fmt.Println("inventory::", err)
+			os.Exit(2)
+		}
+		fmt.Println("put inventory " + inventory.Id + " of oss://" + bucket + " OK")
+	case "rm":
+		if param == "" {
+			fmt.Println("inventory rm miss inventory_id")
+			os.Exit(0)
+		}
+		if _, err := client.DeleteBucketInventory(bucket, param); err != nil {
+			fmt.Println("inventory::", err)
+			os.Exit(2)
+		}
+		fmt.Println("delete inventory " + param + " of oss://" + bucket + " OK")
+	default:
+		fmt.Println("unsupported inventory command : " + args[1])
+		os.Exit(0)
+	}
+}
+
 func Config(config map[string]string) {
 	if config["accessid"] == "" || config["accesskey"] == "" {
 		fmt.Println("config miss parameters, use --id=[accessid] --key=[accesskey] to specify id/key pair")
@@ -428,6 +1020,82 @@ func parse_bucket_object(path string) (string, string) {
 	return bucket, strings.TrimLeft(object, "/")
 }
 
+func symlink_target(bucket, symlink string) string {
+	tmp, err := client.GetSymlink(bucket, symlink)
+	if err != nil {
+		return "?"
+	}
+	return "oss://" + bucket + "/" + tmp["Target"]
+}
+
+// get|put|rm oss://bucket [config.json|config.xml]，put时先把文件解析到config再调用put
+func bucket_config_command(name string, args []string, config interface{},
+	get func(bucket string) (interface{}, error),
+	put func(bucket string) (map[string]string, error),
+	rm func(bucket string) (map[string]string, error)) {
+	if len(args) < 3 {
+		fmt.Println(name + " miss parameters")
+		os.Exit(0)
+	}
+	bucket, _ := parse_bucket_object(args[2])
+	switch args[1] {
+	case "get":
+		tmp, err := get(bucket)
+		if err != nil {
+			fmt.Println(name+"::", err)
+			os.Exit(2)
+		}
+		print_json(tmp)
+	case "put":
+		if len(args) < 4 {
+			fmt.Println(name + " put miss config file")
+			os.Exit(0)
+		}
+		read_config_file(args[3], config)
+		if _, err := put(bucket); err != nil {
+			fmt.Println(name+"::", err)
+			os.Exit(2)
+		}
+		fmt.Println("put " + name + " of oss://" + bucket + " OK")
+	case "rm":
+		if _, err := rm(bucket); err != nil {
+			fmt.Println(name+"::", err)
+			os.Exit(2)
+		}
+		fmt.Println("delete " + name + " of oss://" + bucket + " OK")
+	default:
+		fmt.Println("unsupported " + name + " command : " + args[1])
+		os.Exit(0)
+	}
+}
+
+// .xml按XML解析，其他按JSON解析
+func read_config_file(file string, v interface{}) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		fmt.Println("read::", err)
+		os.Exit(2)
+	}
+	if strings.HasSuffix(strings.ToLower(file), ".xml") {
+		err = xml.Unmarshal(data, v)
+	} else {
+		err = json.Unmarshal(data, v)
+	}
+	if err != nil {
+		fmt.Println("parse::", file, err)
+		os.Exit(2)
+	}
+}
+
+func print_json(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Println("json::", err)
+		os.Exit(2)
+	}
+	fmt.Println(string(data))
+}
+
 func size_format(size int) string {
 	unit := "B"
 	b := float64(size)
@@ -0,0 +1,96 @@
+package osscmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"lib/aliyun/oss"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// batch manifest.jsonl [result.jsonl]，manifest为-时从标准输入读取，.csv结尾的按csv格式读取
+//
+// 结果按完成顺序写入result(默认manifest.result.jsonl)，可直接作为manifest重新执行失败的项
+func Batch(args []string, options map[string]string) {
+	if len(args) < 2 {
+		fmt.Println("batch miss parameters")
+		os.Exit(0)
+	}
+	manifest := args[1]
+	resultFile := manifest + ".result.jsonl"
+	if manifest == "-" {
+		resultFile = "batch.result.jsonl"
+	}
+	if len(args) > 2 {
+		resultFile = args[2]
+	}
+	format := "jsonl"
+	if strings.HasSuffix(strings.ToLower(manifest), ".csv") {
+		format = "csv"
+	}
+	var input io.Reader = os.Stdin
+	if manifest != "-" {
+		fd, err := os.Open(manifest)
+		if err != nil {
+			fmt.Println("batch::", err)
+			os.Exit(2)
+		}
+		defer fd.Close()
+		input = fd
+	}
+	entries, err := oss.ReadManifest(input, format)
+	if err != nil {
+		fmt.Println("batch::", err)
+		os.Exit(2)
+	}
+	if options["dry-run"] == "true" {
+		total := 0
+		for _, entry := range entries {
+			if entry.Status == oss.ManifestOK {
+				continue
+			}
+			fmt.Printf("%-12s %s -> %s\n", entry.Action, entry.Src, entry.Dst)
+			total++
+		}
+		fmt.Println("\nTotal to be transferred num: " + strconv.Itoa(total))
+		return
+	}
+
+	result, err := os.Create(resultFile)
+	if err != nil {
+		fmt.Println("batch::", err)
+		os.Exit(2)
+	}
+	defer result.Close()
+	encoder := json.NewEncoder(result)
+	encoder.SetEscapeHTML(false)
+	done, finish, fail := 0, 0, 0
+	for res := range client.ExecuteManifest(entries, map[string]string{
+		"thread_num": options["thread_num"],
+		"sse":        options["sse"],
+		"sse-key-id": options["sse_key_id"],
+	}) {
+		if err := encoder.Encode(res); err != nil {
+			fmt.Println("batch::", err)
+			os.Exit(2)
+		}
+		done++
+		if res.Status == oss.ManifestOK {
+			finish++
+		} else {
+			fail++
+			fmt.Printf("\n%s Fail,%s -> %s:%s\n", res.Action, res.Src, res.Dst, res.Error)
+		}
+		//实时进度
+		fmt.Printf("\r%d/%d", done, len(entries))
+	}
+	res := "\nTotal being transferred num: " + strconv.Itoa(len(entries)) + "\n"
+	res += "OK num:" + strconv.Itoa(finish) + ", FAIL num:" + strconv.Itoa(fail) + "\n"
+	res += "result manifest: " + resultFile + "\n"
+	fmt.Println(res)
+	if fail > 0 {
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,227 @@
+package osscmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"lib/aliyun/oss"
+	"os"
+	"strings"
+)
+
+// bucketconfig export/import使用的JSON格式，未配置的项为空
+type BucketConfig struct {
+	Bucket     string                        `json:"Bucket"`
+	ACL        string                        `json:"ACL,omitempty"`
+	Versioning *oss.VersioningConfiguration  `json:"Versioning,omitempty"`
+	Logging    *oss.BucketLoggingStatus      `json:"Logging,omitempty"`
+	Encryption *oss.ServerSideEncryptionRule `json:"Encryption,omitempty"`
+	Tagging    *oss.Tagging                  `json:"Tagging,omitempty"`
+	Lifecycle  *oss.LifecycleConfiguration   `json:"Lifecycle,omitempty"`
+	CORS       *oss.CORSConfiguration        `json:"CORS,omitempty"`
+	Referer    *oss.RefererConfiguration     `json:"Referer,omitempty"`
+	Website    *oss.WebsiteConfiguration     `json:"Website,omitempty"`
+	Policy     json.RawMessage               `json:"Policy,omitempty"`
+}
+
+func BucketConfigCommand(args []string) {
+	if len(args) < 3 {
+		fmt.Println("bucketconfig miss parameters")
+		os.Exit(0)
+	}
+	bucket, _ := parse_bucket_object(args[2])
+	switch args[1] {
+	case "export":
+		print_json(export_bucket_config(bucket))
+	case "import":
+		var config BucketConfig
+		if len(args) > 3 {
+			read_config_file(args[3], &config)
+		} else {
+			if err := json.NewDecoder(os.Stdin).Decode(&config); err != nil {
+				fmt.Println("bucketconfig::", err)
+				os.Exit(2)
+			}
+		}
+		import_bucket_config(bucket, &config)
+	default:
+		fmt.Println("unsupported bucketconfig command : " + args[1])
+		os.Exit(0)
+	}
+}
+
+func export_bucket_config(bucket string) *BucketConfig {
+	config := &BucketConfig{Bucket: bucket}
+	acl, err := client.GetBucketACL(bucket)
+	check_bucket_config("acl", err)
+	if acl != nil {
+		config.ACL = acl.Grant
+	}
+	versioning, err := client.GetBucketVersioning(bucket)
+	if check_bucket_config("versioning", err) && versioning.Status != "" {
+		config.Versioning = versioning
+	}
+	logging, err := client.GetBucketLogging(bucket)
+	if check_bucket_config("logging", err) && logging.LoggingEnabled != nil {
+		config.Logging = logging
+	}
+	encryption, err := client.GetBucketEncryption(bucket)
+	if check_bucket_config("encryption", err) {
+		config.Encryption = encryption
+	}
+	tagging, err := client.GetBucketTagging(bucket)
+	if check_bucket_config("tagging", err) && len(tagging.Tags) > 0 {
+		config.Tagging = tagging
+	}
+	lifecycle, err := client.GetBucketLifecycle(bucket)
+	if check_bucket_config("lifecycle", err) {
+		config.Lifecycle = lifecycle
+	}
+	cors, err := client.GetBucketCORS(bucket)
+	if check_bucket_config("cors", err) {
+		config.CORS = cors
+	}
+	referer, err := client.GetBucketReferer(bucket)
+	if check_bucket_config("referer", err) && !is_default_referer(referer) {
+		config.Referer = referer
+	}
+	website, err := client.GetBucketWebsite(bucket)
+	if check_bucket_config("website", err) {
+		config.Website = website
+	}
+	policy, err := client.GetBucketPolicy(bucket)
+	if check_bucket_config("policy", err) && json.Valid([]byte(policy)) {
+		config.Policy = json.RawMessage(policy)
+	}
+	return config
+}
+
+// 未配置防盗链时OSS返回允许空Referer、名单为空的默认配置，导出时省略
+func is_default_referer(referer *oss.RefererConfiguration) bool {
+	if !referer.AllowEmptyReferer || (referer.AllowTruncateQueryString != nil && !*referer.AllowTruncateQueryString) {
+		return false
+	}
+	for _, list := range [][]string{referer.RefererList, referer.RefererBlacklist} {
+		for _, v := range list {
+			if v != "" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type bucket_config_step struct {
+	name  string
+	apply func() (map[string]string, error)
+}
+
+// 先校验全部配置，有错误时不做任何修改；导入时某项失败继续导入其余项，最后汇总失败项
+// 版本控制先于生命周期设置，历史版本规则依赖版本控制
+func import_bucket_config(bucket string, config *BucketConfig) {
+	if errs := validate_bucket_config(config); len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Println("bucketconfig::", err)
+		}
+		fmt.Println("nothing imported")
+		os.Exit(2)
+	}
+	steps := make([]bucket_config_step, 0)
+	add := func(name string, apply func() (map[string]string, error)) {
+		steps = append(steps, bucket_config_step{name: name, apply: apply})
+	}
+	if config.ACL != "" {
+		add("acl", func() (map[string]string, error) { return client.PutBucketACL(bucket, config.ACL) })
+	}
+	if config.Versioning != nil {
+		add("versioning", func() (map[string]string, error) { return client.PutBucketVersioning(bucket, config.Versioning) })
+	}
+	if config.Logging != nil {
+		add("logging", func() (map[string]string, error) { return client.PutBucketLogging(bucket, config.Logging) })
+	}
+	if config.Encryption != nil {
+		add("encryption", func() (map[string]string, error) { return client.PutBucketEncryption(bucket, config.Encryption) })
+	}
+	if config.Tagging != nil {
+		add("tagging", func() (map[string]string, error) { return client.PutBucketTagging(bucket, config.Tagging) })
+	}
+	if config.Lifecycle != nil {
+		add("lifecycle", func() (map[string]string, error) { return client.PutBucketLifecycle(bucket, config.Lifecycle) })
+	}
+	if config.CORS != nil {
+		add("cors", func() (map[string]string, error) { return client.PutBucketCORS(bucket, config.CORS) })
+	}
+	if config.Referer != nil {
+		add("referer", func() (map[string]string, error) { return client.PutBucketReferer(bucket, config.Referer) })
+	}
+	if config.Website != nil {
+		add("website", func() (map[string]string, error) { return client.PutBucketWebsite(bucket, config.Website) })
+	}
+	if len(config.Policy) > 0 {
+		add("policy", func() (map[string]string, error) { return client.PutBucketPolicy(bucket, string(config.Policy)) })
+	}
+
+	failed := make([]string, 0)
+	for _, step := range steps {
+		if _, err := step.apply(); err != nil {
+			fmt.Println("bucketconfig::"+step.name+"::", err)
+			failed = append(failed, step.name)
+			continue
+		}
+		fmt.Println("import " + step.name + " OK")
+	}
+	if len(failed) > 0 {
+		fmt.Println("import failed: " + strings.Join(failed, ",") + ", fix them and import again")
+		os.Exit(2)
+	}
+}
+
+// 使用各项Put接口相同的校验
+func validate_bucket_config(config *BucketConfig) []error {
+	errs := make([]error, 0)
+	check := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if config.ACL != "" {
+		check(oss.ValidateBucketACL(config.ACL))
+	}
+	if config.Versioning != nil {
+		check(config.Versioning.Validate())
+	}
+	if config.Logging != nil {
+		check(config.Logging.Validate())
+	}
+	if config.Encryption != nil {
+		check(config.Encryption.Validate())
+	}
+	if config.Tagging != nil {
+		check(config.Tagging.Validate())
+	}
+	if config.Lifecycle != nil {
+		check(config.Lifecycle.Validate())
+	}
+	if config.CORS != nil {
+		check(config.CORS.Validate())
+	}
+	if config.Website != nil {
+		check(config.Website.Validate())
+	}
+	if len(config.Policy) > 0 {
+		check(oss.ValidateBucketPolicy(string(config.Policy)))
+	}
+	return errs
+}
+
+// 未配置(404)返回false，其他错误直接退出
+func check_bucket_config(name string, err error) bool {
+	if err == nil {
+		return true
+	}
+	if ossErr, ok := err.(*oss.ErrorResult); ok && ossErr.StatusCode == "404" {
+		return false
+	}
+	fmt.Fprintln(os.Stderr, "bucketconfig::"+name+"::", err)
+	os.Exit(2)
+	return false
+}
@@ -0,0 +1,135 @@
+package osscmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// rm --from-file=keys.txt oss://bucket，每行一个key，-表示从标准输入读取
+//
+// 删除失败的key写入--failed-file(默认keys.txt.failed)，每行一个key，可直接作为--from-file重试，失败原因输出到标准输出
+func DeleteFromFile(args []string, options map[string]string) {
+	if len(args) < 2 {
+		fmt.Println("rm miss parameters")
+		os.Exit(0)
+	}
+	bucket, prefix := parse_bucket_object(args[1])
+	if prefix != "" {
+		fmt.Println("rm::--from-file only accepts oss://bucket")
+		os.Exit(0)
+	}
+	fromFile := options["from-file"]
+	failedFile := options["failed-file"]
+	if failedFile == "" {
+		failedFile = fromFile + ".failed"
+		if fromFile == "-" {
+			failedFile = "rm.failed"
+		}
+	}
+	var input io.Reader = os.Stdin
+	if fromFile != "-" {
+		fd, err := os.Open(fromFile)
+		if err != nil {
+			fmt.Println("rm::", err)
+			os.Exit(2)
+		}
+		defer fd.Close()
+		input = fd
+	}
+
+	if options["dry-run"] == "true" {
+		total := 0
+		err := read_keys(input, func(key string) {
+			fmt.Println("delete oss://" + bucket + "/" + key)
+			total++
+		})
+		if err != nil {
+			fmt.Println("rm::", err)
+			os.Exit(2)
+		}
+		fmt.Println("\nTotal to be deleted objects num: " + strconv.Itoa(total))
+		return
+	}
+	if options["force"] != "true" {
+		//标准输入用于读取key，无法确认
+		if fromFile == "-" {
+			fmt.Println("rm::--from-file=- requires --force=true")
+			os.Exit(0)
+		}
+		fmt.Println("DELETE all objects listed in " + fromFile + " from oss://" + bucket + "? y/N, default is N: ")
+		reader := bufio.NewReader(os.Stdin)
+		confirm, _ := reader.ReadString('\n')
+		if strings.ToUpper(strings.Trim(confirm, "\n")) != "Y" {
+			fmt.Println("quit.")
+			os.Exit(0)
+		}
+	}
+
+	failed, err := os.Create(failedFile)
+	if err != nil {
+		fmt.Println("rm::", err)
+		os.Exit(2)
+	}
+	defer failed.Close()
+	var readErr error
+	keys := make(chan string, 1000)
+	go func() {
+		readErr = read_keys(input, func(key string) { keys <- key })
+		close(keys)
+	}()
+
+	total, finish := 0, 0
+	for batch := range client.DeleteStream(bucket, keys, map[string]string{
+		"thread_num": options["thread_num"],
+		"quiet":      "true",
+	}) {
+		for _, v := range batch.Failed() {
+			fmt.Fprintln(failed, v.Key)
+			fmt.Printf("\ndelete Fail,oss://%s/%s:%s %s\n", bucket, v.Key, v.Code, v.Message)
+		}
+		total += len(batch.Keys)
+		finish += batch.Succeeded()
+		//实时进度
+		fmt.Printf("\r%d", finish)
+	}
+	if readErr != nil {
+		fmt.Println("rm::", readErr)
+		os.Exit(2)
+	}
+	fail := total - finish
+	res := "\nTotal being deleted objects num: " + strconv.Itoa(total) + "\n"
+	res += "OK num:" + strconv.Itoa(finish) + ", FAIL num:" + strconv.Itoa(fail) + "\n"
+	if fail > 0 {
+		res += "failed keys: " + failedFile + "\n"
+	} else {
+		//全部删除成功时不保留空的失败文件
+		failed.Close()
+		os.Remove(failedFile)
+	}
+	fmt.Println(res)
+	if fail > 0 {
+		os.Exit(2)
+	}
+}
+
+// 逐行读取key，忽略空行，key两端的空白字符保留，只去掉行尾的\r
+func read_keys(input io.Reader, fn func(key string)) error {
+	reader := bufio.NewReader(input)
+	for {
+		line, err := reader.ReadString('\n')
+		key := strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if key != "" {
+			fn(key)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
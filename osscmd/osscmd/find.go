@@ -0,0 +1,233 @@
+package osscmd
+
+import (
+	"bufio"
+	"fmt"
+	"lib/aliyun/oss"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 单次Copy最大支持1GB，同oss.ExecutePlan，超过的object使用CopyLargeFile分片复制
+const findCopyLargeThreshold = 1 << 30
+
+// 按名称、大小、修改时间、存储类型查找object，--exec=delete|copy|restore对结果执行操作
+func Find(args []string, options map[string]string) {
+	if len(args) < 2 {
+		fmt.Println("find miss parameters")
+		os.Exit(0)
+	}
+	bucket, prefix := parse_bucket_object(args[1])
+	filter := parse_object_filter(options)
+	action := options["exec"]
+	targetBucket, targetPrefix := "", ""
+	switch action {
+	case "":
+	case "copy":
+		if len(args) < 3 {
+			fmt.Println("find --exec=copy miss target oss://bucket/[prefix]")
+			os.Exit(0)
+		}
+		targetBucket, targetPrefix = parse_bucket_object(args[2])
+	case "delete":
+		if options["force"] != "true" {
+			fmt.Println("DELETE all matched objects? y/N, default is N: ")
+			reader := bufio.NewReader(os.Stdin)
+			input, _ := reader.ReadString('\n')
+			if strings.ToUpper(strings.Trim(input, "\n")) != "Y" {
+				fmt.Println("quit.")
+				os.Exit(0)
+			}
+		}
+	case "restore":
+	default:
+		fmt.Println("find::unsupported exec action: " + action)
+		os.Exit(0)
+	}
+
+	threadNum, _ := strconv.Atoi(options["thread_num"])
+	if threadNum < 1 {
+		threadNum = 1
+	}
+	var wg sync.WaitGroup
+	var queueMaxSize = make(chan bool, threadNum)
+	tmpFinish := int64(0)
+	total := 0
+	totalSize := int64(0)
+	exec := func(key string, size int64) {
+		var res map[string]string
+		var err error
+		switch action {
+		case "delete":
+			res, err = client.Delete(bucket, key)
+			err = find_exec_result(res, err, "204")
+		case "copy":
+			//保留相对源前缀所在目录的路径
+			object := strings.TrimPrefix(key, prefix[:strings.LastIndex(prefix, "/")+1])
+			if targetPrefix != "" {
+				object = strings.TrimRight(targetPrefix, "/") + "/" + object
+			}
+			copyOptions := map[string]string{
+				"sse":        options["sse"],
+				"sse-key-id": options["sse_key_id"],
+			}
+			if size > findCopyLargeThreshold {
+				copyOptions["thread_num"] = options["thread_num"]
+				_, err = client.CopyLargeFile(targetBucket, object, "/"+bucket+"/"+key, copyOptions)
+				break
+			}
+			res, err = client.Copy(targetBucket, object, "/"+bucket+"/"+key, copyOptions)
+			err = find_exec_result(res, err, "200")
+		case "restore":
+			//202表示已开始解冻，200表示已解冻
+			res, err = client.RestoreObject(bucket, key, map[string]string{})
+			err = find_exec_result(res, err, "200", "202")
+		}
+		if err != nil {
+			fmt.Printf("find::%s::oss://%s/%s::%s\n", action, bucket, key, err)
+			return
+		}
+		atomic.AddInt64(&tmpFinish, 1)
+	}
+	largeObjects := make([]oss.ObjectIteratorItem, 0)
+	list, listErr := client.Find(bucket, prefix, filter, threadNum, nil)
+	for v := range list {
+		total++
+		totalSize += v.SizeInt64()
+		lastModified, _ := time.Parse("2006-01-02T15:04:05.000Z", v.LastModified)
+		tmpDatetime := time.Unix(lastModified.Unix(), 0).Format(dateTimeFormat)
+		fmt.Println(tmpDatetime + " " + size_format(int(v.SizeInt64())) + " " + v.StorageClass + " oss://" + bucket + "/" + v.Key)
+		if action == "" {
+			continue
+		}
+		if action == "copy" && v.SizeInt64() > findCopyLargeThreshold {
+			largeObjects = append(largeObjects, v)
+			continue
+		}
+		wg.Add(1)
+		queueMaxSize <- true
+		go func(key string, size int64) {
+			defer wg.Done()
+			defer func() { <-queueMaxSize }()
+			exec(key, size)
+		}(v.Key, v.SizeInt64())
+	}
+	wg.Wait()
+	//大文件在其他object复制完成后逐个分片复制，分片本身是并发的
+	for _, v := range largeObjects {
+		exec(v.Key, v.SizeInt64())
+	}
+	if err := <-listErr; err != nil {
+		fmt.Println("find::", err)
+		os.Exit(2)
+	}
+	res := fmt.Sprintf("\nmatched objects num: %d, totalsize: %s\n", total, size_format(int(totalSize)))
+	if action != "" {
+		finish := int(atomic.LoadInt64(&tmpFinish))
+		res += fmt.Sprintf("%s OK num:%d, FAIL num:%d\n", action, finish, total-finish)
+	}
+	fmt.Println(res)
+}
+
+func find_exec_result(res map[string]string, err error, statusCodes ...string) error {
+	if err != nil {
+		return err
+	}
+	for _, statusCode := range statusCodes {
+		if res["StatusCode"] == statusCode {
+			return nil
+		}
+	}
+	return fmt.Errorf("StatusCode:%s, %s", res["StatusCode"], res["Body"])
+}
+
+func parse_object_filter(options map[string]string) *oss.ObjectFilter {
+	filter := &oss.ObjectFilter{
+		Name:         options["name"],
+		StorageClass: options["storage_class"],
+	}
+	var err error
+	//path.Match只在匹配到错误位置时才报告语法错误，这里提前检查整个pattern
+	if filter.Name != "" {
+		if _, err = path.Match(filter.Name, ""); err != nil {
+			fmt.Println("find::name::", err)
+			os.Exit(0)
+		}
+	}
+	if options["regex"] != "" {
+		if filter.Regex, err = regexp.Compile(options["regex"]); err != nil {
+			fmt.Println("find::regex::", err)
+			os.Exit(0)
+		}
+	}
+	if filter.Larger, err = parse_size(options["larger"]); err != nil {
+		fmt.Println("find::larger::", err)
+		os.Exit(0)
+	}
+	if filter.Smaller, err = parse_size(options["smaller"]); err != nil {
+		fmt.Println("find::smaller::", err)
+		os.Exit(0)
+	}
+	if filter.OlderThan, err = parse_time(options["older"]); err != nil {
+		fmt.Println("find::older::", err)
+		os.Exit(0)
+	}
+	if filter.NewerThan, err = parse_time(options["newer"]); err != nil {
+		fmt.Println("find::newer::", err)
+		os.Exit(0)
+	}
+	return filter
+}
+
+// 1024、10K、500M、1G、2T
+func parse_size(size string) (int64, error) {
+	if size == "" {
+		return 0, nil
+	}
+	units := map[byte]int64{'K': 1 << 10, 'M': 1 << 20, 'G': 1 << 30, 'T': 1 << 40}
+	size = strings.ToUpper(size)
+	if len(size) > 1 {
+		size = strings.TrimSuffix(size, "B")
+	}
+	unit := int64(1)
+	if n, ok := units[size[len(size)-1]]; ok && len(size) > 1 {
+		unit = n
+		size = size[:len(size)-1]
+	}
+	b, err := strconv.ParseFloat(size, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(b * float64(unit)), nil
+}
+
+// 相对当前时间的90d、12h、30m、10s，或2006-01-02、2006-01-02 15:04:05格式的本地时间
+func parse_time(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation(dateTimeFormat, value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Now().AddDate(0, 0, -days), nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Now().Add(-duration), nil
+}
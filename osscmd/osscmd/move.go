@@ -0,0 +1,67 @@
+package osscmd
+
+import (
+	"fmt"
+	"lib/aliyun/oss"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// mv oss://bucket/object oss://bucket/[object]，源以/结尾或为bucket时移动整个前缀
+func Move(args []string, options map[string]string) {
+	if len(args) < 3 {
+		fmt.Println("mv miss parameters")
+		os.Exit(0)
+	}
+	sourceBucket, sourceObject := parse_bucket_object(args[1])
+	sourceFullObject := "/" + sourceBucket + "/" + sourceObject
+	bucket, object := parse_bucket_object(args[2])
+	moveOptions := filter_options(map[string]string{
+		"thread_num": options["thread_num"],
+		"sse":        options["sse"],
+		"sse-key-id": options["sse_key_id"],
+	}, options)
+
+	if sourceObject != "" && !strings.HasSuffix(sourceObject, "/") {
+		if object == "" || strings.HasSuffix(object, "/") {
+			object += path.Base(sourceObject)
+		}
+		if options["dry-run"] == "true" {
+			print_plan(&oss.Plan{Actions: []oss.PlanAction{{Action: oss.PlanMove, Bucket: bucket, Key: object, Source: sourceFullObject}}})
+			return
+		}
+		_, err := client.Move(bucket, object, sourceFullObject, moveOptions)
+		if err != nil {
+			fmt.Println("mv::", err)
+			os.Exit(2)
+		}
+		fmt.Println("move oss:/" + sourceFullObject + " to oss://" + bucket + "/" + object + " OK")
+		return
+	}
+
+	plan, err := client.MovePrefixPlan(bucket, object, sourceFullObject, moveOptions)
+	if err != nil {
+		fmt.Println("mv::", err)
+		os.Exit(2)
+	}
+	if options["dry-run"] == "true" {
+		print_plan(plan)
+		return
+	}
+	tmp, err := client.ExecutePlan(plan, moveOptions)
+	if err != nil {
+		fmt.Println("mv::", err)
+		os.Exit(2)
+	}
+	finish := strconv.Itoa(tmp["finish"])
+	skip := strconv.Itoa(tmp["skip"])
+	fail := strconv.Itoa(tmp["total"] - tmp["finish"] - tmp["skip"])
+	res := "\nTotal being moved objects num: " + strconv.Itoa(tmp["total"]) + ", from " + args[1] + " to " + args[2] + "\n"
+	res += "OK num:" + finish + ", SKIP num:" + skip + ", FAIL num:" + fail + "\n"
+	if tmp["total"] != tmp["finish"]+tmp["skip"] {
+		res += "run the same command again to resume\n"
+	}
+	fmt.Println(res)
+}
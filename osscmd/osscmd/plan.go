@@ -0,0 +1,43 @@
+package osscmd
+
+import (
+	"fmt"
+	"lib/aliyun/oss"
+)
+
+// --dry-run时输出执行计划中的每个操作，不做任何修改
+func print_plan(plan *oss.Plan) {
+	for _, action := range plan.Actions {
+		target := action.LocalFile
+		if action.Key != "" {
+			target = "oss://" + action.Bucket + "/" + action.Key
+		}
+		switch action.Action {
+		case oss.PlanCopy, oss.PlanMove:
+			target = "oss:/" + action.Source + " -> " + target
+		case oss.PlanUpload:
+			target = action.LocalFile + " -> " + target
+		case oss.PlanDownload:
+			target = "oss://" + action.Bucket + "/" + action.Key + " -> " + action.LocalFile
+		}
+		fmt.Printf("%-12s %10s  %-14s %s\n", action.Action, size_format(int(action.Size)), action.Reason, target)
+	}
+	fmt.Println()
+	print_plan_summary(plan)
+}
+
+func print_plan_summary(plan *oss.Plan) {
+	for _, action := range []string{oss.PlanUpload, oss.PlanDownload, oss.PlanCopy, oss.PlanMove, oss.PlanDelete, oss.PlanDeleteLocal, oss.PlanMkdir, oss.PlanSkip} {
+		if count := plan.Count(action); count > 0 {
+			fmt.Printf("%-12s %8d %10s\n", action, count, size_format(int(plan.Size(action))))
+		}
+	}
+}
+
+// 复制include、exclude等过滤规则参数
+func filter_options(dst, options map[string]string) map[string]string {
+	for _, k := range []string{"include", "exclude", "include-regex", "exclude-regex", "exclude-from", "ignore-file"} {
+		dst[k] = options[k]
+	}
+	return dst
+}
@@ -0,0 +1,65 @@
+package osscmd
+
+import (
+	"bufio"
+	"fmt"
+	"lib/aliyun/oss"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// sync localdir oss://bucket/prefix 或 sync oss://bucket/prefix localdir
+func Sync(args []string, options map[string]string) {
+	if len(args) < 3 {
+		fmt.Println("sync miss parameters")
+		os.Exit(0)
+	}
+	direction := oss.SyncUpload
+	localdir, remote := args[1], args[2]
+	if strings.HasPrefix(args[1], "oss://") {
+		direction = oss.SyncDownload
+		localdir, remote = args[2], args[1]
+	}
+	bucket, prefix := parse_bucket_object(remote)
+	syncOptions := filter_options(map[string]string{
+		"delete":     options["delete"],
+		"compare":    options["compare"],
+		"suffix":     options["suffix"],
+		"thread_num": options["thread_num"],
+		"sse":        options["sse"],
+		"sse-key-id": options["sse_key_id"],
+	}, options)
+	plan, err := client.SyncPlan(localdir, bucket, prefix, direction, syncOptions)
+	if err != nil {
+		fmt.Println("sync::", err)
+		os.Exit(2)
+	}
+	if options["dry-run"] == "true" {
+		print_plan(plan)
+		return
+	}
+	print_plan_summary(plan)
+
+	deleteNum := plan.Count(oss.PlanDelete) + plan.Count(oss.PlanDeleteLocal)
+	if deleteNum > 0 && options["force"] != "true" {
+		fmt.Printf("DELETE %d extraneous objects/files? y/N, default is N: \n", deleteNum)
+		reader := bufio.NewReader(os.Stdin)
+		input, _ := reader.ReadString('\n')
+		if strings.ToUpper(strings.Trim(input, "\n")) != "Y" {
+			fmt.Println("quit.")
+			os.Exit(0)
+		}
+	}
+	tmp, err := client.ExecutePlan(plan, syncOptions)
+	if err != nil {
+		fmt.Println("sync::", err)
+		os.Exit(2)
+	}
+	finish := strconv.Itoa(tmp["finish"])
+	skip := strconv.Itoa(tmp["skip"])
+	fail := strconv.Itoa(tmp["total"] - tmp["finish"] - tmp["skip"])
+	res := "\nTotal being synced num: " + strconv.Itoa(tmp["total"]) + ", from " + args[1] + " to " + args[2] + "\n"
+	res += "OK num:" + finish + ", SKIP num:" + skip + ", FAIL num:" + fail + "\n"
+	fmt.Println(res)
+}
@@ -0,0 +1,106 @@
+package osscmd
+
+import (
+	"fmt"
+	"lib/aliyun/oss"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 按目录统计object数量和大小，--depth为展开的目录层数，默认1
+func DiskUsage(args []string, options map[string]string) {
+	if len(args) < 2 {
+		fmt.Println("du miss parameters")
+		os.Exit(0)
+	}
+	bucket, prefix := parse_bucket_object(args[1])
+	depth, err := strconv.Atoi(options["depth"])
+	if err != nil || depth < 0 {
+		depth = 1
+	}
+	usage, err := client.DiskUsage(bucket, prefix, depth)
+	if err != nil {
+		fmt.Println("du::", err)
+		os.Exit(2)
+	}
+	print_dir_usage(bucket, usage)
+	fmt.Println("\nstorage class:")
+	for _, class := range usage.StorageClasses() {
+		classUsage := usage.StorageClass[class]
+		fmt.Printf("%-12s %10s %10d\n", class, size_format(int(classUsage.Size)), classUsage.Count)
+	}
+}
+
+// 子目录在前，与du命令的输出顺序一致
+func print_dir_usage(bucket string, usage *oss.DirUsage) {
+	for _, dir := range usage.Dirs {
+		print_dir_usage(bucket, dir)
+	}
+	classes := make([]string, 0)
+	for _, class := range usage.StorageClasses() {
+		classUsage := usage.StorageClass[class]
+		classes = append(classes, fmt.Sprintf("%s:%s/%d", class, size_format(int(classUsage.Size)), classUsage.Count))
+	}
+	fmt.Printf("%10s %10d  oss://%s/%s  [%s]\n", size_format(int(usage.Size)), usage.Count, bucket, usage.Prefix, strings.Join(classes, " "))
+}
+
+// 以树状结构输出目录和object，--depth为展开的目录层数，默认不限
+func Tree(args []string, options map[string]string) {
+	if len(args) < 2 {
+		fmt.Println("tree miss parameters")
+		os.Exit(0)
+	}
+	bucket, prefix := parse_bucket_object(args[1])
+	depth, err := strconv.Atoi(options["depth"])
+	if err != nil || depth <= 0 {
+		depth = -1
+	}
+	fmt.Println("oss://" + bucket + "/" + prefix)
+	dirNum, fileNum := print_tree(bucket, prefix, "", depth)
+	fmt.Printf("\n%d directories, %d files\n", dirNum, fileNum)
+}
+
+func print_tree(bucket, prefix, indent string, depth int) (int, int) {
+	dirNum, fileNum := 0, 0
+	list := client.NewObjectIterator(bucket, map[string]string{"prefix": prefix, "delimiter": "/"})
+	defer list.Close()
+	//多取一项，用于判断当前项是否为目录下的最后一项
+	var item *oss.ObjectIteratorItem
+	for {
+		hasNext := list.Next()
+		if item != nil {
+			branch, childIndent := "├── ", indent+"│   "
+			if !hasNext {
+				branch, childIndent = "└── ", indent+"    "
+			}
+			name := strings.TrimPrefix(item.Key, prefix)
+			if item.IsPrefix {
+				dirNum++
+				fmt.Println(indent + branch + name)
+				if depth != 1 {
+					childDirNum, childFileNum := print_tree(bucket, item.Key, childIndent, depth-1)
+					dirNum += childDirNum
+					fileNum += childFileNum
+				}
+			} else {
+				fileNum++
+				fmt.Println(indent + branch + name + " (" + size_format(int(item.SizeInt64())) + ")")
+			}
+		}
+		if !hasNext {
+			break
+		}
+		next := list.Object()
+		item = &next
+		//跳过目录占位object
+		if item.Key == prefix {
+			item = nil
+		}
+	}
+	if err := list.Err(); err != nil {
+		fmt.Println("tree::", err)
+		os.Exit(2)
+	}
+	return dirNum, fileNum
+}
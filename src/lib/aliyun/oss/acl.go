@@ -0,0 +1,40 @@
+package oss
+
+import (
+	"encoding/xml"
+	"errors"
+)
+
+type AccessControlPolicy struct {
+	Owner ObjectOwner `xml:"Owner"`
+	Grant string      `xml:"AccessControlList>Grant"`
+}
+
+func (this *Client) GetBucketACL(bucket string) (*AccessControlPolicy, error) {
+	res, err := this.bucketRequest("GET", bucket, "?acl", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var acl AccessControlPolicy
+	if err := xml.Unmarshal([]byte(res["Body"]), &acl); err != nil {
+		return nil, err
+	}
+	return &acl, nil
+}
+
+// acl: private、public-read、public-read-write
+func ValidateBucketACL(acl string) error {
+	switch acl {
+	case "private", "public-read", "public-read-write":
+	default:
+		return errors.New("unsupported acl: " + acl)
+	}
+	return nil
+}
+
+func (this *Client) PutBucketACL(bucket, acl string) (map[string]string, error) {
+	if err := ValidateBucketACL(acl); err != nil {
+		return nil, err
+	}
+	return this.bucketRequest("PUT", bucket, "?acl", "", nil, map[string]string{"x-oss-acl": acl})
+}
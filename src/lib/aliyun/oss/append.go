@@ -0,0 +1,132 @@
+package oss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"path"
+	"strconv"
+	"time"
+)
+
+type AppendWriter struct {
+	client   *Client
+	bucket   string
+	object   string
+	position int64
+	closed   bool
+
+	CRC64 string
+}
+
+func (this *Client) AppendObject(bucket, object string, position int64, reader io.Reader) (map[string]string, error) {
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	subResource := fmt.Sprintf("?append&position=%d", position)
+	addr := fmt.Sprintf("http://%s%s/%s%s", bucket, this.host, object, subResource)
+	method := "POST"
+	contentType := mime.TypeByExtension(path.Ext(object))
+	contentLength := strconv.Itoa(len(body))
+	contentMd5 := this.base64(this.md5Byte(body))
+	date := time.Unix(time.Now().Unix()-8*3600, 0).Format(this.dateTimeGMT)
+	headers := map[string]string{
+		"Content-Md5":  contentMd5,
+		"Content-Type": contentType,
+		"Date":         date,
+	}
+	headers["Authorization"] = this.sign(method, headers, bucket, object+subResource)
+	headers["Content-Length"] = contentLength
+	res, err := this.curl(addr, method, headers, body)
+	if err != nil {
+		return nil, err
+	}
+	if res["StatusCode"] != "200" {
+		return nil, this.parseError(res)
+	}
+	return map[string]string{
+		"X-Oss-Request-Id": res["X-Oss-Request-Id"],
+		"StatusCode":       res["StatusCode"],
+		"Location":         "http://" + bucket + this.host + "/" + object,
+		"Bucket":           bucket,
+		"ETag":             res["Etag"],
+		"Key":              object,
+		"NextPosition":     res["X-Oss-Next-Append-Position"],
+		"CRC64":            res["X-Oss-Hash-Crc64ecma"],
+	}, nil
+}
+
+// 从object当前长度开始追加，object不存在时从0开始
+func (this *Client) NewAppendWriter(bucket, object string) (*AppendWriter, error) {
+	position, err := this.appendPosition(bucket, object)
+	if err != nil {
+		return nil, err
+	}
+	return &AppendWriter{
+		client:   this,
+		bucket:   bucket,
+		object:   object,
+		position: position,
+	}, nil
+}
+
+func (this *Client) appendPosition(bucket, object string) (int64, error) {
+	objectHead, err := this.Head(bucket, object)
+	if err != nil {
+		return 0, err
+	}
+	if objectHead["StatusCode"] == "404" {
+		return 0, nil
+	}
+	if objectHead["StatusCode"] != "200" {
+		return 0, errors.New("StatusCode:" + objectHead["StatusCode"])
+	}
+	return strconv.ParseInt(objectHead["Content-Length"], 10, 64)
+}
+
+func (this *AppendWriter) Write(p []byte) (int, error) {
+	if this.closed {
+		return 0, errors.New("AppendWriter: write on closed writer")
+	}
+	var res map[string]string
+	var err error
+	for i := 0; i < this.client.maxRetryNum; i++ {
+		res, err = this.client.AppendObject(this.bucket, this.object, this.position, bytes.NewReader(p))
+		if err == nil {
+			break
+		}
+		//其他写入方修改了object长度，重新获取position
+		if ossErr, ok := err.(*ErrorResult); ok && ossErr.Code == "PositionNotEqualToLength" {
+			position, headErr := this.client.appendPosition(this.bucket, this.object)
+			if headErr != nil {
+				return 0, headErr
+			}
+			this.position = position
+			continue
+		}
+		return 0, err
+	}
+	if err != nil {
+		return 0, err
+	}
+	nextPosition, err := strconv.ParseInt(res["NextPosition"], 10, 64)
+	if err != nil {
+		nextPosition = this.position + int64(len(p))
+	}
+	this.position = nextPosition
+	this.CRC64 = res["CRC64"]
+	return len(p), nil
+}
+
+func (this *AppendWriter) Position() int64 {
+	return this.position
+}
+
+func (this *AppendWriter) Close() error {
+	this.closed = true
+	return nil
+}
@@ -0,0 +1,200 @@
+package oss
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ListBucketsResult struct {
+	Prefix      string        `xml:"Prefix"`
+	Marker      string        `xml:"Marker"`
+	MaxKeys     string        `xml:"MaxKeys"`
+	IsTruncated string        `xml:"IsTruncated"`
+	NextMarker  string        `xml:"NextMarker"`
+	Owner       ObjectOwner   `xml:"Owner"`
+	Buckets     []BucketEntry `xml:"Buckets>Bucket"`
+}
+
+type BucketEntry struct {
+	Name             string `xml:"Name"`
+	CreationDate     string `xml:"CreationDate"`
+	Location         string `xml:"Location"`
+	Region           string `xml:"Region"`
+	ExtranetEndpoint string `xml:"ExtranetEndpoint"`
+	IntranetEndpoint string `xml:"IntranetEndpoint"`
+	StorageClass     string `xml:"StorageClass"`
+}
+
+type CreateBucketConfiguration struct {
+	XMLName            xml.Name `xml:"CreateBucketConfiguration"`
+	StorageClass       string   `xml:"StorageClass,omitempty"`
+	DataRedundancyType string   `xml:"DataRedundancyType,omitempty"`
+}
+
+type BucketInfoResult struct {
+	Bucket BucketInfo `xml:"Bucket"`
+}
+
+type BucketInfo struct {
+	Name                     string                   `xml:"Name"`
+	CreationDate             string                   `xml:"CreationDate"`
+	Location                 string                   `xml:"Location"`
+	ExtranetEndpoint         string                   `xml:"ExtranetEndpoint"`
+	IntranetEndpoint         string                   `xml:"IntranetEndpoint"`
+	StorageClass             string                   `xml:"StorageClass"`
+	DataRedundancyType       string                   `xml:"DataRedundancyType"`
+	TransferAcceleration     string                   `xml:"TransferAcceleration"`
+	CrossRegionReplication   string                   `xml:"CrossRegionReplication"`
+	Versioning               string                   `xml:"Versioning"`
+	ResourceGroupId          string                   `xml:"ResourceGroupId"`
+	Comment                  string                   `xml:"Comment"`
+	Owner                    ObjectOwner              `xml:"Owner"`
+	Grant                    string                   `xml:"AccessControlList>Grant"`
+	ServerSideEncryptionRule BucketInfoEncryptionRule `xml:"ServerSideEncryptionRule"`
+	LogBucket                string                   `xml:"BucketPolicy>LogBucket"`
+	LogPrefix                string                   `xml:"BucketPolicy>LogPrefix"`
+}
+
+type BucketInfoEncryptionRule struct {
+	SSEAlgorithm      string `xml:"SSEAlgorithm"`
+	KMSMasterKeyID    string `xml:"KMSMasterKeyID"`
+	KMSDataEncryption string `xml:"KMSDataEncryption"`
+}
+
+type BucketLocationResult struct {
+	Location string `xml:",chardata"`
+}
+
+// bucket级别请求，subResource如"?bucketInfo"参与签名，query为不参与签名的查询参数
+// signHeaders为需要签名的x-oss-*头，也可用Content-Type覆盖默认的application/xml
+func (this *Client) bucketRequest(method, bucket, subResource, query string, body []byte, signHeaders map[string]string) (map[string]string, error) {
+	addr := "http://" + bucket + this.host + "/" + subResource
+	if bucket == "" {
+		addr = "http://" + strings.TrimLeft(this.host, ".") + "/" + subResource
+	}
+	if query != "" {
+		if subResource == "" {
+			addr += "?" + query
+		} else {
+			addr += "&" + query
+		}
+	}
+	date := time.Unix(time.Now().Unix()-8*3600, 0).Format(this.dateTimeGMT)
+	headers := map[string]string{
+		"Date": date,
+	}
+	for k, v := range signHeaders {
+		headers[k] = v
+	}
+	//签名使用未编码的子资源值
+	resource, err := url.PathUnescape(subResource)
+	if err != nil {
+		return nil, err
+	}
+	LF := "\n"
+	if len(body) > 0 {
+		headers["Content-Md5"] = this.base64(this.md5Byte(body))
+		if headers["Content-Type"] == "" {
+			headers["Content-Type"] = "application/xml"
+		}
+		headers["Authorization"] = this.sign(method, headers, bucket, resource)
+		headers["Content-Length"] = strconv.Itoa(len(body))
+	} else {
+		headers["Authorization"] = this.sign(method+LF+LF, headers, bucket, resource)
+	}
+	res, err := this.curl(addr, method, headers, body)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(res["StatusCode"], "2") {
+		return nil, this.parseError(res)
+	}
+	return res, nil
+}
+
+func (this *Client) ListBuckets(options map[string]string) (*ListBucketsResult, error) {
+	param := ""
+	for _, k := range []string{"marker", "max-keys", "prefix"} {
+		if options[k] != "" {
+			param += "&" + k + "=" + url.QueryEscape(options[k])
+		}
+	}
+	res, err := this.bucketRequest("GET", "", "", strings.TrimLeft(param, "&"), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var listBuckets ListBucketsResult
+	if err := xml.Unmarshal([]byte(res["Body"]), &listBuckets); err != nil {
+		return nil, err
+	}
+	return &listBuckets, nil
+}
+
+// options: acl(private/public-read/public-read-write)、storage_class、redundancy(LRS/ZRS)
+func (this *Client) CreateBucket(bucket string, options map[string]string) (map[string]string, error) {
+	ossHeaders := map[string]string{}
+	if options["acl"] != "" {
+		ossHeaders["x-oss-acl"] = options["acl"]
+	}
+	var body []byte
+	if options["storage_class"] != "" || options["redundancy"] != "" {
+		config := CreateBucketConfiguration{
+			StorageClass:       options["storage_class"],
+			DataRedundancyType: options["redundancy"],
+		}
+		tmp, err := xml.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+		body = tmp
+	}
+	res, err := this.bucketRequest("PUT", bucket, "", "", body, ossHeaders)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"X-Oss-Request-Id": res["X-Oss-Request-Id"],
+		"StatusCode":       res["StatusCode"],
+		"Bucket":           bucket,
+		"Location":         res["Location"],
+	}, nil
+}
+
+func (this *Client) DeleteBucket(bucket string) (map[string]string, error) {
+	res, err := this.bucketRequest("DELETE", bucket, "", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"X-Oss-Request-Id": res["X-Oss-Request-Id"],
+		"StatusCode":       res["StatusCode"],
+		"Bucket":           bucket,
+	}, nil
+}
+
+func (this *Client) GetBucketInfo(bucket string) (*BucketInfo, error) {
+	res, err := this.bucketRequest("GET", bucket, "?bucketInfo", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var bucketInfo BucketInfoResult
+	if err := xml.Unmarshal([]byte(res["Body"]), &bucketInfo); err != nil {
+		return nil, err
+	}
+	return &bucketInfo.Bucket, nil
+}
+
+func (this *Client) GetBucketLocation(bucket string) (string, error) {
+	res, err := this.bucketRequest("GET", bucket, "?location", "", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	var location BucketLocationResult
+	if err := xml.Unmarshal([]byte(res["Body"]), &location); err != nil {
+		return "", err
+	}
+	return location.Location, nil
+}
@@ -6,10 +6,13 @@ import (
 	"crypto/md5"
 	"crypto/sha1"
 	"encoding/base64"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
@@ -17,6 +20,18 @@ import (
 	"strings"
 )
 
+type ErrorResult struct {
+	StatusCode string `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+	RequestId  string `xml:"RequestId"`
+	HostId     string `xml:"HostId"`
+}
+
+func (this *ErrorResult) Error() string {
+	return fmt.Sprintf("StatusCode:%s, Code:%s, Message:%s, RequestId:%s", this.StatusCode, this.Code, this.Message, this.RequestId)
+}
+
 type Client struct {
 	host            string
 	accessKeyId     string
@@ -86,6 +101,104 @@ func (this *Client) curl(addr string, method string, headers map[string]string,
 	return this.curl2Reader(addr, method, headers, bytes.NewReader(body))
 }
 
+// 服务端加密：AES256、KMS、SM4，KMS可指定key id
+func (this *Client) setEncryptionHeaders(headers map[string]string, options map[string]string) error {
+	sse := options["sse"]
+	keyId := options["sse-key-id"]
+	switch sse {
+	case "":
+		if keyId != "" {
+			return errors.New("sse-key-id requires sse=KMS")
+		}
+		return nil
+	case "AES256", "SM4":
+		if keyId != "" {
+			return errors.New("sse-key-id requires sse=KMS")
+		}
+	case "KMS":
+		if keyId != "" {
+			headers["x-oss-server-side-encryption-key-id"] = keyId
+		}
+	default:
+		return errors.New("unsupported sse: " + sse)
+	}
+	headers["x-oss-server-side-encryption"] = sse
+	return nil
+}
+
+// 自定义元数据：options中以x-oss-meta-开头的key，以及非空的x-oss-storage-class、x-oss-tagging原样作为请求头
+func (this *Client) setMetaHeaders(headers map[string]string, options map[string]string) {
+	for k, v := range options {
+		if strings.HasPrefix(k, "x-oss-meta-") || ((k == "x-oss-storage-class" || k == "x-oss-tagging") && v != "") {
+			headers[k] = v
+		}
+	}
+}
+
+// 复制options中以x-oss-开头的元数据、存储类型、标签参数到dst，用于只转发部分options的调用
+func (this *Client) metaOptions(dst map[string]string, options map[string]string) map[string]string {
+	for k, v := range options {
+		if strings.HasPrefix(k, "x-oss-") {
+			dst[k] = v
+		}
+	}
+	return dst
+}
+
+// 分片复制时需要从源object带到目标object的标准头
+var copyStandardHeaders = []string{"Content-Type", "Content-Disposition", "Content-Encoding", "Cache-Control", "Expires"}
+
+// 源object的标准头和x-oss-meta-*转为options参数(小写key)，用于分片复制时保留源object的元数据，
+// options中指定了x-oss-meta-*时同Copy替换元数据，不保留源object的x-oss-meta-*
+func (this *Client) sourceMetaOptions(dst map[string]string, sourceHead map[string]string, options map[string]string) map[string]string {
+	for _, k := range copyStandardHeaders {
+		if sourceHead[k] != "" {
+			dst[strings.ToLower(k)] = sourceHead[k]
+		}
+	}
+	if !hasMetaOptions(options) {
+		for k, v := range sourceHead {
+			if strings.HasPrefix(strings.ToLower(k), "x-oss-meta-") {
+				dst[strings.ToLower(k)] = v
+			}
+		}
+	}
+	return dst
+}
+
+func hasMetaOptions(options map[string]string) bool {
+	for k := range options {
+		if strings.HasPrefix(k, "x-oss-meta-") {
+			return true
+		}
+	}
+	return false
+}
+
+// 可选的options参数，未传时返回空map
+func (this *Client) firstOptions(options []map[string]string) map[string]string {
+	if len(options) > 0 && options[0] != nil {
+		return options[0]
+	}
+	return map[string]string{}
+}
+
+// 指定版本时返回请求地址和签名使用的?versionId=xxx
+func (this *Client) versionIdParam(versionId string) (string, string) {
+	if versionId == "" {
+		return "", ""
+	}
+	return "?versionId=" + url.QueryEscape(versionId), "?versionId=" + versionId
+}
+
+func (this *Client) parseError(res map[string]string) error {
+	ossErr := &ErrorResult{StatusCode: res["StatusCode"]}
+	if err := xml.Unmarshal([]byte(res["Body"]), ossErr); err != nil {
+		return errors.New("StatusCode:" + res["StatusCode"])
+	}
+	return ossErr
+}
+
 func (this *Client) sign(method string, headers map[string]string, bucket, object string) string {
 	var keyList []string
 	LF := "\n"
@@ -101,7 +214,12 @@ func (this *Client) sign(method string, headers map[string]string, bucket, objec
 			sign += headers[key] + LF
 		}
 	}
-	sign += "/" + bucket + "/" + object
+	//无bucket的请求(如ListBuckets)签名资源为/
+	if bucket == "" {
+		sign += "/" + object
+	} else {
+		sign += "/" + bucket + "/" + object
+	}
 	return "OSS " + this.accessKeyId + ":" + this.base64([]byte(this.hmac(sign, this.accessKeySecret)))
 }
 
@@ -125,7 +243,8 @@ func (this *Client) md5(data []byte) string {
 	return fmt.Sprintf("%x", sum)
 }
 
-func (this *Client) walkdir(localdir string, suffix string) []string {
+// 返回localdir下文件的相对路径，suffix为逗号分隔的后缀(不区分大小写)，filter为nil时不过滤
+func (this *Client) walkdir(localdir string, suffix string, filter *PathFilter) []string {
 	var list = make([]string, 0)
 	localdir = strings.TrimRight(localdir, "/") + "/"
 	localdir = filepath.Dir(localdir)
@@ -144,23 +263,25 @@ func (this *Client) walkdir(localdir string, suffix string) []string {
 		fileName = strings.Replace(fileName, localdir, "", 1)
 		fileName = strings.TrimLeft(fileName, "/")
 
-		allowed := true
-		if suffix != "" {
-			suffixList := strings.Split(suffix, ",")
-			for _, tmpSuffix := range suffixList {
-				if tmpSuffix != "" {
-					allowed = false
-					if strings.HasSuffix(strings.ToLower(fileName), tmpSuffix) {
-						allowed = true
-						break
-					}
-				}
-			}
-		}
-		if allowed {
+		if matchSuffix(fileName, suffix) && filter.Match(fileName) {
 			list = append(list, fileName)
 		}
 		return nil
 	})
 	return list
 }
+
+// 文件名是否以逗号分隔的后缀之一结尾(不区分大小写)，suffix为空时都匹配
+func matchSuffix(name, suffix string) bool {
+	allowed := true
+	for _, tmpSuffix := range strings.Split(suffix, ",") {
+		tmpSuffix = strings.ToLower(strings.TrimSpace(tmpSuffix))
+		if tmpSuffix != "" {
+			allowed = false
+			if strings.HasSuffix(strings.ToLower(name), tmpSuffix) {
+				return true
+			}
+		}
+	}
+	return allowed
+}
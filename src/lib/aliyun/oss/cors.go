@@ -0,0 +1,69 @@
+package oss
+
+import (
+	"encoding/xml"
+	"errors"
+)
+
+type CORSConfiguration struct {
+	XMLName      xml.Name   `xml:"CORSConfiguration" json:"-"`
+	Rules        []CORSRule `xml:"CORSRule" json:"Rules"`
+	ResponseVary *bool      `xml:"ResponseVary,omitempty" json:"ResponseVary,omitempty"`
+}
+
+type CORSRule struct {
+	AllowedOrigins []string `xml:"AllowedOrigin" json:"AllowedOrigins"`
+	AllowedMethods []string `xml:"AllowedMethod" json:"AllowedMethods"`
+	AllowedHeaders []string `xml:"AllowedHeader,omitempty" json:"AllowedHeaders,omitempty"`
+	ExposeHeaders  []string `xml:"ExposeHeader,omitempty" json:"ExposeHeaders,omitempty"`
+	MaxAgeSeconds  int      `xml:"MaxAgeSeconds,omitempty" json:"MaxAgeSeconds,omitempty"`
+}
+
+func (this *CORSConfiguration) Validate() error {
+	if len(this.Rules) == 0 {
+		return errors.New("cors: no rules")
+	}
+	if len(this.Rules) > 10 {
+		return errors.New("cors: at most 10 rules")
+	}
+	for _, rule := range this.Rules {
+		if len(rule.AllowedOrigins) == 0 || len(rule.AllowedMethods) == 0 {
+			return errors.New("cors rule: AllowedOrigins and AllowedMethods are required")
+		}
+		for _, method := range rule.AllowedMethods {
+			switch method {
+			case "GET", "PUT", "DELETE", "POST", "HEAD":
+			default:
+				return errors.New("cors rule: unsupported method " + method)
+			}
+		}
+	}
+	return nil
+}
+
+func (this *Client) GetBucketCORS(bucket string) (*CORSConfiguration, error) {
+	res, err := this.bucketRequest("GET", bucket, "?cors", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var cors CORSConfiguration
+	if err := xml.Unmarshal([]byte(res["Body"]), &cors); err != nil {
+		return nil, err
+	}
+	return &cors, nil
+}
+
+func (this *Client) PutBucketCORS(bucket string, cors *CORSConfiguration) (map[string]string, error) {
+	if err := cors.Validate(); err != nil {
+		return nil, err
+	}
+	body, err := xml.Marshal(cors)
+	if err != nil {
+		return nil, err
+	}
+	return this.bucketRequest("PUT", bucket, "?cors", "", body, nil)
+}
+
+func (this *Client) DeleteBucketCORS(bucket string) (map[string]string, error) {
+	return this.bucketRequest("DELETE", bucket, "?cors", "", nil, nil)
+}
@@ -0,0 +1,181 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/textproto"
+)
+
+const (
+	AesCtrAlgorithm = "AES/CTR/NoPadding"
+	AesGcmAlgorithm = "AES/GCM/NoPadding"
+
+	metaPrefix = "x-oss-meta-client-side-encryption-"
+)
+
+const (
+	metaKey                      = metaPrefix + "key"
+	metaStart                    = metaPrefix + "start"
+	metaCekAlg                   = metaPrefix + "cek-alg"
+	metaWrapAlg                  = metaPrefix + "wrap-alg"
+	metaMatDesc                  = metaPrefix + "matdesc"
+	metaUnencryptedContentLength = metaPrefix + "unencrypted-content-length"
+	metaUnencryptedContentMd5    = metaPrefix + "unencrypted-content-md5"
+	metaDataSize                 = metaPrefix + "data-size"
+	metaPartSize                 = metaPrefix + "part-size"
+)
+
+// 每个object独立的数据密钥和IV
+type contentCipher struct {
+	algorithm string
+	key       []byte
+	iv        []byte
+}
+
+func newContentCipher(algorithm string) (*contentCipher, error) {
+	ivSize := aes.BlockSize
+	switch algorithm {
+	case AesCtrAlgorithm:
+	case AesGcmAlgorithm:
+		ivSize = 12
+	default:
+		return nil, errors.New("unsupported content encryption algorithm: " + algorithm)
+	}
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	iv := make([]byte, ivSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+	return &contentCipher{algorithm: algorithm, key: key, iv: iv}, nil
+}
+
+// CTR模式可从任意offset开始加解密，GCM只能处理完整object
+func (this *contentCipher) encrypt(data []byte, offset int64) ([]byte, error) {
+	if this.algorithm == AesGcmAlgorithm {
+		if offset != 0 {
+			return nil, errors.New(AesGcmAlgorithm + " does not support offset")
+		}
+		gcm, err := this.gcm()
+		if err != nil {
+			return nil, err
+		}
+		return gcm.Seal(nil, this.iv, data, nil), nil
+	}
+	return this.xorAt(data, offset)
+}
+
+func (this *contentCipher) decrypt(data []byte, offset int64) ([]byte, error) {
+	if this.algorithm == AesGcmAlgorithm {
+		if offset != 0 {
+			return nil, errors.New(AesGcmAlgorithm + " does not support offset")
+		}
+		gcm, err := this.gcm()
+		if err != nil {
+			return nil, err
+		}
+		return gcm.Open(nil, this.iv, data, nil)
+	}
+	return this.xorAt(data, offset)
+}
+
+func (this *contentCipher) xorAt(data []byte, offset int64) ([]byte, error) {
+	block, err := aes.NewCipher(this.key)
+	if err != nil {
+		return nil, err
+	}
+	stream := cipher.NewCTR(block, counterAdd(this.iv, uint64(offset/aes.BlockSize)))
+	//跳过offset所在块中offset之前的字节
+	if skip := offset % aes.BlockSize; skip > 0 {
+		tmp := make([]byte, skip)
+		stream.XORKeyStream(tmp, tmp)
+	}
+	out := make([]byte, len(data))
+	stream.XORKeyStream(out, data)
+	return out, nil
+}
+
+func (this *contentCipher) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(this.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// 数据密钥和IV均由MasterKey加密后存入元数据
+func (this *contentCipher) envelope(masterKey MasterKey) (map[string]string, error) {
+	wrappedKey, err := masterKey.Encrypt(this.key)
+	if err != nil {
+		return nil, err
+	}
+	wrappedIv, err := masterKey.Encrypt(this.iv)
+	if err != nil {
+		return nil, err
+	}
+	meta := map[string]string{
+		metaKey:     base64.StdEncoding.EncodeToString(wrappedKey),
+		metaStart:   base64.StdEncoding.EncodeToString(wrappedIv),
+		metaCekAlg:  this.algorithm,
+		metaWrapAlg: masterKey.WrapAlgorithm(),
+	}
+	if masterKey.MatDesc() != "" {
+		meta[metaMatDesc] = masterKey.MatDesc()
+	}
+	return meta, nil
+}
+
+// res为Head/Cat返回的响应头
+func openEnvelope(res map[string]string, masterKey MasterKey) (*contentCipher, error) {
+	if res[canonicalMeta(metaKey)] == "" {
+		return nil, nil
+	}
+	if wrapAlg := res[canonicalMeta(metaWrapAlg)]; wrapAlg != masterKey.WrapAlgorithm() {
+		return nil, errors.New("object is wrapped with " + wrapAlg + ", master key uses " + masterKey.WrapAlgorithm())
+	}
+	wrappedKey, err := base64.StdEncoding.DecodeString(res[canonicalMeta(metaKey)])
+	if err != nil {
+		return nil, err
+	}
+	wrappedIv, err := base64.StdEncoding.DecodeString(res[canonicalMeta(metaStart)])
+	if err != nil {
+		return nil, err
+	}
+	key, err := masterKey.Decrypt(wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := masterKey.Decrypt(wrappedIv)
+	if err != nil {
+		return nil, err
+	}
+	algorithm := res[canonicalMeta(metaCekAlg)]
+	if algorithm != AesCtrAlgorithm && algorithm != AesGcmAlgorithm {
+		return nil, errors.New("unsupported content encryption algorithm: " + algorithm)
+	}
+	return &contentCipher{algorithm: algorithm, key: key, iv: iv}, nil
+}
+
+// 响应头的key已被net/http规范化，如X-Oss-Meta-Client-Side-Encryption-Key
+func canonicalMeta(key string) string {
+	return textproto.CanonicalMIMEHeaderKey(key)
+}
+
+// IV作为128位大端整数加上块序号
+func counterAdd(iv []byte, blocks uint64) []byte {
+	counter := make([]byte, len(iv))
+	copy(counter, iv)
+	carry := blocks
+	for i := len(counter) - 1; i >= 0 && carry > 0; i-- {
+		sum := uint64(counter[i]) + (carry & 0xff)
+		counter[i] = byte(sum)
+		carry = (carry >> 8) + (sum >> 8)
+	}
+	return counter
+}
@@ -0,0 +1,74 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"testing"
+)
+
+func TestCTROffsets(t *testing.T) {
+	//IV全为0xff，计数器加1时需要跨字节进位
+	contentCipher := &contentCipher{algorithm: AesCtrAlgorithm, key: bytes.Repeat([]byte{1}, 32), iv: bytes.Repeat([]byte{0xff}, aes.BlockSize)}
+	plain := make([]byte, 5000)
+	for i := range plain {
+		plain[i] = byte(i * 7)
+	}
+	block, err := aes.NewCipher(contentCipher.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//对照：从头连续加密，计数器溢出后按128位回绕
+	want := make([]byte, len(plain))
+	counter := append([]byte{}, contentCipher.iv...)
+	keystream := make([]byte, aes.BlockSize)
+	for off := 0; off < len(plain); off += aes.BlockSize {
+		block.Encrypt(keystream, counter)
+		for i := off; i < off+aes.BlockSize && i < len(plain); i++ {
+			want[i] = plain[i] ^ keystream[i-off]
+		}
+		counter = counterAdd(counter, 1)
+	}
+	whole, err := contentCipher.encrypt(plain, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(whole, want) {
+		t.Fatal("encrypt from offset 0 differs from sequential CTR")
+	}
+	for _, off := range []int{0, 1, 15, 16, 17, 255, 256, 4095, 4096, 4999} {
+		encrypted, err := contentCipher.encrypt(plain[off:], int64(off))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(encrypted, want[off:]) {
+			t.Fatalf("encrypt at offset %d differs", off)
+		}
+		decrypted, err := contentCipher.decrypt(want[off:], int64(off))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decrypted, plain[off:]) {
+			t.Fatalf("decrypt at offset %d differs", off)
+		}
+	}
+	//标准库CTR同样是128位大端计数器
+	stream := cipher.NewCTR(block, contentCipher.iv)
+	std := make([]byte, len(plain))
+	stream.XORKeyStream(std, plain)
+	if !bytes.Equal(std, want) {
+		t.Fatal("counter does not match crypto/cipher CTR")
+	}
+}
+
+func TestCounterAdd(t *testing.T) {
+	iv := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xfe}
+	got := counterAdd(iv, 0x103)
+	want := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01, 0x01, 0x01}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("counterAdd = %x, want %x", got, want)
+	}
+	if iv[15] != 0xfe {
+		t.Fatal("counterAdd modified iv")
+	}
+}
@@ -0,0 +1,388 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"lib/aliyun/oss"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Client在oss.Client之上做客户端信封加密，只提供加密上传和解密下载，
+// 不暴露oss.Client的其他方法，避免误用明文写入
+type Client struct {
+	client    *oss.Client
+	masterKey MasterKey
+
+	partMaxSize  int
+	partMinSize  int
+	threadMaxNum int
+	threadMinNum int
+	maxRetryNum  int
+
+	Algorithm string
+}
+
+func New(client *oss.Client, masterKey MasterKey) *Client {
+	return &Client{
+		client:    client,
+		masterKey: masterKey,
+
+		partMaxSize:  100 * 1024 * 1024,
+		partMinSize:  1 * 1024 * 1024,
+		threadMaxNum: 100,
+		threadMinNum: 5,
+		maxRetryNum:  3,
+
+		Algorithm: AesCtrAlgorithm,
+	}
+}
+
+func (this *Client) Put(body []byte, bucket, object string, options map[string]string) (map[string]string, error) {
+	contentCipher, err := newContentCipher(this.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := contentCipher.encrypt(body, 0)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := contentCipher.envelope(this.masterKey)
+	if err != nil {
+		return nil, err
+	}
+	sum := md5.Sum(body)
+	meta[metaUnencryptedContentLength] = strconv.Itoa(len(body))
+	meta[metaUnencryptedContentMd5] = base64.StdEncoding.EncodeToString(sum[:])
+	res, err := this.client.Put(encrypted, bucket, object, mergeOptions(options, meta))
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, errors.New("put encrypted object fail: " + object)
+	}
+	return res, nil
+}
+
+func (this *Client) UploadFile(filePath, bucket, object string, options map[string]string) (map[string]string, error) {
+	body, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if object == "" {
+		object = path.Base(filePath)
+	}
+	if strings.TrimRight(object, "/") == path.Dir(object) {
+		object = strings.TrimRight(object, "/") + "/" + path.Base(filePath)
+	}
+	return this.Put(body, bucket, object, options)
+}
+
+// 分片大小按AES块大小对齐，每个分片从其offset对应的计数器开始加密
+func (this *Client) UploadLargeFile(filePath, bucket, object string, options map[string]string) (map[string]string, error) {
+	var wg sync.WaitGroup
+	if this.Algorithm != AesCtrAlgorithm {
+		return nil, errors.New("multipart upload requires " + AesCtrAlgorithm)
+	}
+	fd, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	partSize := this.partMaxSize
+	if options["partsize"] != "" {
+		tmpPartSize, err := strconv.Atoi(options["partsize"])
+		if err == nil && tmpPartSize <= this.partMaxSize && tmpPartSize >= this.partMinSize {
+			partSize = tmpPartSize
+		}
+	}
+	partSize -= partSize % aes.BlockSize
+	threadNum := this.threadMaxNum
+	if options["thread_num"] != "" {
+		tmpThreadNum, err := strconv.Atoi(options["thread_num"])
+		if err == nil && tmpThreadNum <= this.threadMaxNum && tmpThreadNum >= this.threadMinNum {
+			threadNum = tmpThreadNum
+		}
+	}
+	if object == "" {
+		object = path.Base(filePath)
+	}
+	if strings.TrimRight(object, "/") == path.Dir(object) {
+		object = strings.TrimRight(object, "/") + "/" + path.Base(filePath)
+	}
+	fileStat, err := fd.Stat()
+	if err != nil {
+		return nil, err
+	}
+	fileSize := fileStat.Size()
+	var total = int((fileSize + int64(partSize) - 1) / int64(partSize))
+	if total < threadNum {
+		threadNum = total
+	}
+	if threadNum < 1 {
+		threadNum = 1
+	}
+
+	contentCipher, err := newContentCipher(AesCtrAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := contentCipher.envelope(this.masterKey)
+	if err != nil {
+		return nil, err
+	}
+	meta[metaUnencryptedContentLength] = strconv.FormatInt(fileSize, 10)
+	meta[metaDataSize] = strconv.FormatInt(fileSize, 10)
+	meta[metaPartSize] = strconv.Itoa(partSize)
+	//初化化上传
+	initUpload, err := this.client.InitUpload(bucket, object, mergeOptions(options, meta))
+	if err != nil {
+		return nil, err
+	}
+	if initUpload["UploadId"] == "" {
+		return nil, errors.New("init multipart upload fail: " + object)
+	}
+	var uploadPartList = make([]map[string]string, total)
+	var uploadErr error
+	var uploadErrOnce sync.Once
+	var queueMaxSize = make(chan bool, threadNum)
+	var uploadPercent = make(chan bool)
+	var uploadDone = make(chan struct{})
+
+	//实时进度
+	go func() {
+		finishNum := 0
+		for {
+			_, ok := <-uploadPercent
+			if !ok {
+				close(uploadDone)
+				break
+			}
+			finishNum++
+			fmt.Printf("\r%.0f%%", float64(finishNum)/float64(total)*100)
+		}
+	}()
+
+	for partNum := 0; partNum < total; partNum++ {
+		wg.Add(1)
+		queueMaxSize <- true
+		go func(partNum int) {
+			defer wg.Done()
+			defer func() { <-queueMaxSize }()
+			off := int64(partNum) * int64(partSize)
+			num := int64(partSize)
+			if fileSize-off < num {
+				num = fileSize - off
+			}
+			plain := make([]byte, num)
+			if _, err := fd.ReadAt(plain, off); err != nil && err != io.EOF {
+				uploadErrOnce.Do(func() { uploadErr = err })
+				return
+			}
+			encrypted, err := contentCipher.encrypt(plain, off)
+			if err != nil {
+				uploadErrOnce.Do(func() { uploadErr = err })
+				return
+			}
+			body := io.NewSectionReader(bytes.NewReader(encrypted), 0, num)
+			isUploadSuccess := false
+			for i := 0; i < this.maxRetryNum; i++ {
+				body.Seek(0, io.SeekStart)
+				uploadPart, err := this.client.UploadPart(body, bucket, object, partNum+1, initUpload["UploadId"])
+				if err != nil || uploadPart["StatusCode"] != "200" {
+					continue
+				}
+				uploadPartList[partNum] = uploadPart
+				isUploadSuccess = true
+				break
+			}
+			if !isUploadSuccess {
+				uploadErrOnce.Do(func() { uploadErr = fmt.Errorf("upload part fail, PartNum:%d", partNum) })
+				return
+			}
+			uploadPercent <- true
+		}(partNum)
+	}
+	wg.Wait()
+	close(uploadPercent)
+	<-uploadDone
+	if uploadErr != nil {
+		return nil, uploadErr
+	}
+	//上传完成
+	return this.client.CompleteUpload(bucket, object, initUpload["UploadId"], uploadPartList)
+}
+
+// 先用oss.Client下载密文，再在本地文件上原地解密
+func (this *Client) Get(bucket, object, localfile string, options ...map[string]string) (map[string]string, error) {
+	objectHead, err := this.client.Head(bucket, object, options...)
+	if err != nil {
+		return nil, err
+	}
+	if objectHead["StatusCode"] != "200" {
+		return nil, errors.New("StatusCode:" + objectHead["StatusCode"])
+	}
+	contentCipher, err := openEnvelope(objectHead, this.masterKey)
+	if err != nil {
+		return nil, err
+	}
+	res, err := this.client.Get(bucket, object, localfile, options...)
+	if err != nil || contentCipher == nil {
+		return res, err
+	}
+	if err := this.decryptFile(contentCipher, res["localfile"], objectHead); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (this *Client) decryptFile(contentCipher *contentCipher, localfile string, objectHead map[string]string) error {
+	encryptedSize, err := strconv.ParseInt(objectHead["Content-Length"], 10, 64)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(localfile, os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if contentCipher.algorithm == AesGcmAlgorithm {
+		encrypted := make([]byte, encryptedSize)
+		if _, err := file.ReadAt(encrypted, 0); err != nil && err != io.EOF {
+			return err
+		}
+		plain, err := contentCipher.decrypt(encrypted, 0)
+		if err != nil {
+			return err
+		}
+		if _, err := file.WriteAt(plain, 0); err != nil {
+			return err
+		}
+		return file.Truncate(int64(len(plain)))
+	}
+	buf := make([]byte, 1024*1024)
+	for off := int64(0); off < encryptedSize; off += int64(len(buf)) {
+		n, err := file.ReadAt(buf, off)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if int64(n) > encryptedSize-off {
+			n = int(encryptedSize - off)
+		}
+		plain, err := contentCipher.decrypt(buf[:n], off)
+		if err != nil {
+			return err
+		}
+		if _, err := file.WriteAt(plain, off); err != nil {
+			return err
+		}
+	}
+	return file.Truncate(encryptedSize)
+}
+
+// 支持Range，如bytes=100-199，CTR模式只下载并解密所需范围；param[1]为versionId
+func (this *Client) Cat(bucket, object string, param ...string) (map[string]string, error) {
+	partRange := ""
+	if len(param) > 0 {
+		partRange = param[0]
+	}
+	versionId := ""
+	if len(param) > 1 {
+		versionId = param[1]
+	}
+	start, end, err := parseRange(partRange)
+	if err != nil {
+		return nil, err
+	}
+	res, err := this.client.Cat(bucket, object, partRange, versionId)
+	if err != nil {
+		return nil, err
+	}
+	if res["StatusCode"] != "200" && res["StatusCode"] != "206" {
+		return res, nil
+	}
+	contentCipher, err := openEnvelope(res, this.masterKey)
+	if err != nil || contentCipher == nil {
+		return res, err
+	}
+	if contentCipher.algorithm == AesGcmAlgorithm {
+		//GCM只能整体解密，再截取所需范围
+		if partRange != "" {
+			res, err = this.client.Cat(bucket, object, "", versionId)
+			if err != nil {
+				return nil, err
+			}
+		}
+		plain, err := contentCipher.decrypt([]byte(res["Body"]), 0)
+		if err != nil {
+			return nil, err
+		}
+		if partRange != "" {
+			if start >= int64(len(plain)) {
+				plain = []byte{}
+			} else {
+				if end < 0 || end >= int64(len(plain)) {
+					end = int64(len(plain)) - 1
+				}
+				plain = plain[start : end+1]
+			}
+		}
+		res["Body"] = string(plain)
+		return res, nil
+	}
+	if res["StatusCode"] != "206" {
+		start = 0
+	}
+	plain, err := contentCipher.decrypt([]byte(res["Body"]), start)
+	if err != nil {
+		return nil, err
+	}
+	res["Body"] = string(plain)
+	return res, nil
+}
+
+// 返回-1表示不限
+func parseRange(partRange string) (int64, int64, error) {
+	if partRange == "" {
+		return 0, -1, nil
+	}
+	if !strings.HasPrefix(partRange, "bytes=") {
+		return 0, 0, errors.New("invalid range: " + partRange)
+	}
+	tmp := strings.SplitN(strings.TrimPrefix(partRange, "bytes="), "-", 2)
+	if len(tmp) != 2 || tmp[0] == "" {
+		return 0, 0, errors.New("unsupported range: " + partRange)
+	}
+	start, err := strconv.ParseInt(tmp[0], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	end := int64(-1)
+	if tmp[1] != "" {
+		if end, err = strconv.ParseInt(tmp[1], 10, 64); err != nil {
+			return 0, 0, err
+		}
+	}
+	return start, end, nil
+}
+
+func mergeOptions(options map[string]string, meta map[string]string) map[string]string {
+	res := map[string]string{}
+	for k, v := range options {
+		res[k] = v
+	}
+	for k, v := range meta {
+		res[k] = v
+	}
+	return res
+}
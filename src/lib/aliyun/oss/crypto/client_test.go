@@ -0,0 +1,103 @@
+package crypto
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"lib/aliyun/oss"
+	"lib/aliyun/oss/osstest"
+)
+
+func newTestClient(t *testing.T, algorithm string) (*Client, *osstest.Server) {
+	srv := osstest.NewServer()
+	masterKey, err := NewAESMasterKey(bytes.Repeat([]byte{2}, 32), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := New(oss.New(srv.Host, osstest.AccessKeyId, osstest.AccessKeySecret), masterKey)
+	client.Algorithm = algorithm
+	return client, srv
+}
+
+func testData(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i * 31)
+	}
+	return data
+}
+
+func TestCatRange(t *testing.T) {
+	for _, algorithm := range []string{AesCtrAlgorithm, AesGcmAlgorithm} {
+		client, srv := newTestClient(t, algorithm)
+		data := testData(5000)
+		if _, err := client.Put(data, "bucket", "object", nil); err != nil {
+			t.Fatal(err)
+		}
+		object, _ := srv.Object("bucket", "object")
+		if bytes.Contains(object.Data, data[:64]) {
+			t.Fatalf("%s: object stored in plaintext", algorithm)
+		}
+		for _, r := range [][2]int{{0, -1}, {0, 0}, {1, 15}, {15, 16}, {16, 31}, {17, 4095}, {4096, -1}, {4999, 4999}} {
+			partRange, want := fmt.Sprintf("bytes=%d-", r[0]), data[r[0]:]
+			if r[1] >= 0 {
+				partRange, want = fmt.Sprintf("bytes=%d-%d", r[0], r[1]), data[r[0]:r[1]+1]
+			}
+			res, err := client.Cat("bucket", "object", partRange)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal([]byte(res["Body"]), want) {
+				t.Fatalf("%s: Cat %s returned wrong plaintext", algorithm, partRange)
+			}
+		}
+		srv.Close()
+	}
+}
+
+func TestUploadLargeFile(t *testing.T) {
+	client, srv := newTestClient(t, AesCtrAlgorithm)
+	defer srv.Close()
+	client.partMinSize = 100
+	dir, err := ioutil.TempDir("", "osscrypto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	data := testData(5000)
+	localfile := filepath.Join(dir, "large")
+	if err := ioutil.WriteFile(localfile, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	//分片大小不是块大小的整数倍时向下对齐到992
+	if _, err := client.UploadLargeFile(localfile, "bucket", "large", map[string]string{"partsize": "1000", "thread_num": "5"}); err != nil {
+		t.Fatal(err)
+	}
+	object, _ := srv.Object("bucket", "large")
+	if object.Headers["X-Oss-Meta-Client-Side-Encryption-Part-Size"] != "992" {
+		t.Fatalf("part size = %q, want 992", object.Headers["X-Oss-Meta-Client-Side-Encryption-Part-Size"])
+	}
+
+	res, err := client.Cat("bucket", "large", "bytes=980-2000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal([]byte(res["Body"]), data[980:2001]) {
+		t.Fatal("Cat across part boundary returned wrong plaintext")
+	}
+	downloaded := filepath.Join(dir, "downloaded")
+	if _, err := client.Get("bucket", "large", downloaded); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadFile(downloaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("Get returned wrong plaintext")
+	}
+}
@@ -0,0 +1,199 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	RsaWrapAlgorithm = "RSA/NONE/PKCS1Padding"
+	AesWrapAlgorithm = "AES/GCM/NoPadding"
+)
+
+// MasterKey负责加密/解密每个object的数据密钥
+type MasterKey interface {
+	WrapAlgorithm() string
+	MatDesc() string
+	Encrypt(data []byte) ([]byte, error)
+	Decrypt(data []byte) ([]byte, error)
+}
+
+type RSAMasterKey struct {
+	publicKey  *rsa.PublicKey
+	privateKey *rsa.PrivateKey
+	matDesc    string
+}
+
+// 只传公钥时只能上传，只传私钥时公钥从私钥导出
+func NewRSAMasterKey(publicKeyPEM, privateKeyPEM []byte, matDesc string) (*RSAMasterKey, error) {
+	masterKey := &RSAMasterKey{matDesc: matDesc}
+	if len(privateKeyPEM) > 0 {
+		block, _ := pem.Decode(privateKeyPEM)
+		if block == nil {
+			return nil, errors.New("rsa private key: invalid PEM")
+		}
+		privateKey, err := parseRSAPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		masterKey.privateKey = privateKey
+		masterKey.publicKey = &privateKey.PublicKey
+	}
+	if len(publicKeyPEM) > 0 {
+		block, _ := pem.Decode(publicKeyPEM)
+		if block == nil {
+			return nil, errors.New("rsa public key: invalid PEM")
+		}
+		publicKey, err := parseRSAPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		masterKey.publicKey = publicKey
+	}
+	if masterKey.publicKey == nil {
+		return nil, errors.New("rsa master key: missing public and private key")
+	}
+	return masterKey, nil
+}
+
+func NewRSAMasterKeyFromFile(publicKeyFile, privateKeyFile, matDesc string) (*RSAMasterKey, error) {
+	var publicKeyPEM, privateKeyPEM []byte
+	var err error
+	if publicKeyFile != "" {
+		if publicKeyPEM, err = ioutil.ReadFile(publicKeyFile); err != nil {
+			return nil, err
+		}
+	}
+	if privateKeyFile != "" {
+		if privateKeyPEM, err = ioutil.ReadFile(privateKeyFile); err != nil {
+			return nil, err
+		}
+	}
+	return NewRSAMasterKey(publicKeyPEM, privateKeyPEM, matDesc)
+}
+
+func (this *RSAMasterKey) WrapAlgorithm() string {
+	return RsaWrapAlgorithm
+}
+
+func (this *RSAMasterKey) MatDesc() string {
+	return this.matDesc
+}
+
+func (this *RSAMasterKey) Encrypt(data []byte) ([]byte, error) {
+	return rsa.EncryptPKCS1v15(rand.Reader, this.publicKey, data)
+}
+
+func (this *RSAMasterKey) Decrypt(data []byte) ([]byte, error) {
+	if this.privateKey == nil {
+		return nil, errors.New("rsa master key: missing private key")
+	}
+	return rsa.DecryptPKCS1v15(rand.Reader, this.privateKey, data)
+}
+
+func parseRSAPrivateKey(der []byte) (*rsa.PrivateKey, error) {
+	if privateKey, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return privateKey, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("rsa private key: not an RSA key")
+	}
+	return privateKey, nil
+}
+
+func parseRSAPublicKey(der []byte) (*rsa.PublicKey, error) {
+	if publicKey, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		return publicKey, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("rsa public key: not an RSA key")
+	}
+	return publicKey, nil
+}
+
+type AESMasterKey struct {
+	key     []byte
+	matDesc string
+}
+
+func NewAESMasterKey(key []byte, matDesc string) (*AESMasterKey, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, errors.New("aes master key: key size must be 16, 24 or 32 bytes")
+	}
+	return &AESMasterKey{key: key, matDesc: matDesc}, nil
+}
+
+// 密钥文件内容可以是原始字节，也可以是base64编码
+func NewAESMasterKeyFromFile(keyFile, matDesc string) (*AESMasterKey, error) {
+	data, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data))); err == nil {
+		if masterKey, err := NewAESMasterKey(key, matDesc); err == nil {
+			return masterKey, nil
+		}
+	}
+	return NewAESMasterKey(data, matDesc)
+}
+
+func (this *AESMasterKey) WrapAlgorithm() string {
+	return AesWrapAlgorithm
+}
+
+func (this *AESMasterKey) MatDesc() string {
+	return this.matDesc
+}
+
+// 输出为nonce+密文
+func (this *AESMasterKey) Encrypt(data []byte) ([]byte, error) {
+	gcm, err := this.gcm()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+func (this *AESMasterKey) Decrypt(data []byte) ([]byte, error) {
+	gcm, err := this.gcm()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("aes master key: wrapped data too short")
+	}
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+}
+
+func (this *AESMasterKey) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(this.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,212 @@
+package oss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 单次批量删除最多1000个key
+const deleteObjectsMaxKeys = 1000
+
+type DeleteObjectsResult struct {
+	EncodingType string          `xml:"EncodingType"`
+	Deleted      []DeletedObject `xml:"Deleted"`
+	Errors       []DeleteError   `xml:"Error"`
+}
+
+type DeletedObject struct {
+	Key string `xml:"Key"`
+}
+
+type DeleteError struct {
+	Key     string `xml:"Key"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// 批量删除object，quiet为true时只返回删除失败的key，否则同时返回删除成功的key
+func (this *Client) DeleteObjects(bucket string, keys []string, quiet bool) (*DeleteObjectsResult, error) {
+	if len(keys) == 0 {
+		return &DeleteObjectsResult{}, nil
+	}
+	if len(keys) > deleteObjectsMaxKeys {
+		return nil, errors.New("delete objects: too many keys " + strconv.Itoa(len(keys)))
+	}
+	var body bytes.Buffer
+	body.WriteString("<Delete><Quiet>" + strconv.FormatBool(quiet) + "</Quiet>")
+	for _, key := range keys {
+		body.WriteString("<Object><Key>")
+		if err := xml.EscapeText(&body, []byte(key)); err != nil {
+			return nil, err
+		}
+		body.WriteString("</Key></Object>")
+	}
+	body.WriteString("</Delete>")
+
+	//返回的key使用url编码，避免key中的控制字符导致XML解析失败
+	addr := "http://" + bucket + this.host + "/?delete&encoding-type=url"
+	method := "POST"
+	date := time.Unix(time.Now().Unix()-8*3600, 0).Format(this.dateTimeGMT)
+	headers := map[string]string{
+		"Content-Md5": this.base64(this.md5Byte(body.Bytes())) + "\n",
+		"Date":        date,
+	}
+	headers["Authorization"] = this.sign(method, headers, bucket, "?delete")
+	headers["Content-Length"] = strconv.Itoa(body.Len())
+	headers["Content-Md5"] = strings.TrimRight(headers["Content-Md5"], "\n")
+	res, err := this.curl(addr, method, headers, body.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if res["StatusCode"] != "200" {
+		return nil, this.parseError(res)
+	}
+	//quiet模式全部删除成功时没有返回body
+	result := &DeleteObjectsResult{}
+	if strings.TrimSpace(res["Body"]) == "" {
+		return result, nil
+	}
+	if err := xml.Unmarshal([]byte(res["Body"]), result); err != nil {
+		return nil, err
+	}
+	if result.EncodingType == "url" {
+		for i := range result.Deleted {
+			if result.Deleted[i].Key, err = url.QueryUnescape(result.Deleted[i].Key); err != nil {
+				return nil, err
+			}
+		}
+		for i := range result.Errors {
+			if result.Errors[i].Key, err = url.QueryUnescape(result.Errors[i].Key); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// DeleteStream中一个批次的删除结果，Err不为nil时整批请求失败
+type DeleteBatch struct {
+	Keys   []string
+	Result *DeleteObjectsResult
+	Err    error
+}
+
+// 删除失败的key，整批请求失败时为所有key
+func (this *DeleteBatch) Failed() []DeleteError {
+	if this.Err == nil {
+		return this.Result.Errors
+	}
+	failed := make([]DeleteError, 0, len(this.Keys))
+	for _, key := range this.Keys {
+		failed = append(failed, DeleteError{Key: key, Message: this.Err.Error()})
+	}
+	return failed
+}
+
+// 删除成功的key数量
+func (this *DeleteBatch) Succeeded() int {
+	if this.Err != nil {
+		return 0
+	}
+	return len(this.Keys) - len(this.Result.Errors)
+}
+
+// 从keys流式读取，每1000个key一批并发批量删除，边读取边删除，keys关闭且全部删除完成后关闭返回的channel
+//
+// options：thread_num、quiet(true时不返回删除成功的key)
+func (this *Client) DeleteStream(bucket string, keys <-chan string, options map[string]string) <-chan DeleteBatch {
+	threadNum := this.threadMaxNum
+	if options["thread_num"] != "" {
+		tmpThreadNum, err := strconv.Atoi(options["thread_num"])
+		if err == nil && tmpThreadNum <= this.threadMaxNum && tmpThreadNum >= this.threadMinNum {
+			threadNum = tmpThreadNum
+		}
+	}
+	quiet := options["quiet"] == "true"
+	batches := make(chan []string, threadNum)
+	results := make(chan DeleteBatch, threadNum)
+
+	go func() {
+		batch := make([]string, 0, deleteObjectsMaxKeys)
+		for key := range keys {
+			batch = append(batch, key)
+			if len(batch) == deleteObjectsMaxKeys {
+				batches <- batch
+				batch = make([]string, 0, deleteObjectsMaxKeys)
+			}
+		}
+		if len(batch) > 0 {
+			batches <- batch
+		}
+		close(batches)
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < threadNum; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				var res *DeleteObjectsResult
+				var err error
+				for i := 0; i < this.maxRetryNum; i++ {
+					if res, err = this.DeleteObjects(bucket, batch, quiet); err == nil {
+						break
+					}
+				}
+				results <- DeleteBatch{Keys: batch, Result: res, Err: err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// 删除prefix下的所有object(过滤规则同NewPathFilter)，列举和删除流水线执行，返回total、finish
+//
+// 删除失败的key逐个输出，options同DeleteStream
+func (this *Client) DeleteAllObject(bucket, prefix string, options map[string]string) (map[string]int, error) {
+	filter, err := NewPathFilter(options)
+	if err != nil {
+		return nil, err
+	}
+	tmpTotal := int64(0)
+	keys := make(chan string, deleteObjectsMaxKeys)
+	list, listErr := this.ListParallel(bucket, prefix, this.threadMaxNum, nil)
+	go func() {
+		for v := range list {
+			if !filter.Match(relativeKey(v.Key, prefix)) {
+				continue
+			}
+			atomic.AddInt64(&tmpTotal, 1)
+			keys <- v.Key
+		}
+		close(keys)
+	}()
+
+	finish := 0
+	for batch := range this.DeleteStream(bucket, keys, options) {
+		for _, v := range batch.Failed() {
+			fmt.Printf("\nDelete Fail,oss://%s/%s:%s %s\n", bucket, v.Key, v.Code, v.Message)
+		}
+		finish += batch.Succeeded()
+		//实时进度
+		fmt.Printf("\r%d", finish)
+	}
+	if err := <-listErr; err != nil {
+		return nil, err
+	}
+	total := int(atomic.LoadInt64(&tmpTotal))
+	return map[string]int{"total": total, "finish": finish}, nil
+}
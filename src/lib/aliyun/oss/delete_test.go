@@ -0,0 +1,75 @@
+package oss
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDeleteObjects(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	keys := []string{"a&b<c>.txt", "d e.txt", "fail.txt"}
+	for _, quiet := range []bool{true, false} {
+		for _, key := range keys {
+			srv.PutObject("bucket", key, []byte(key), nil)
+		}
+		srv.DeleteErrors = map[string]string{}
+		res, err := client.DeleteObjects("bucket", keys[:2], quiet)
+		if err != nil {
+			t.Fatalf("quiet=%v: %s", quiet, err)
+		}
+		if len(res.Errors) != 0 {
+			t.Errorf("quiet=%v: errors = %v", quiet, res.Errors)
+		}
+		deleted := 2
+		if quiet {
+			deleted = 0
+		}
+		if len(res.Deleted) != deleted {
+			t.Errorf("quiet=%v: deleted = %v", quiet, res.Deleted)
+		}
+		if keys := srv.Keys("bucket"); len(keys) != 1 || keys[0] != "fail.txt" {
+			t.Errorf("quiet=%v: remaining keys = %v", quiet, keys)
+		}
+
+		srv.DeleteErrors = map[string]string{"fail.txt": "AccessDenied"}
+		res, err = client.DeleteObjects("bucket", keys[2:], quiet)
+		if err != nil {
+			t.Fatalf("quiet=%v: %s", quiet, err)
+		}
+		if len(res.Errors) != 1 || res.Errors[0].Key != "fail.txt" || res.Errors[0].Code != "AccessDenied" {
+			t.Errorf("quiet=%v: errors = %v", quiet, res.Errors)
+		}
+	}
+}
+
+func TestDeleteStreamQuiet(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	keys := make(chan string)
+	go func() {
+		for i := 0; i < 2500; i++ {
+			key := fmt.Sprintf("k%04d", i)
+			srv.PutObject("bucket", key, nil, nil)
+			keys <- key
+		}
+		close(keys)
+	}()
+	srv.DeleteErrors["k1234"] = "AccessDenied"
+	succeeded, batches := 0, 0
+	failed := make([]DeleteError, 0)
+	for batch := range client.DeleteStream("bucket", keys, map[string]string{"quiet": "true"}) {
+		if batch.Err != nil {
+			t.Fatal(batch.Err)
+		}
+		batches++
+		succeeded += batch.Succeeded()
+		failed = append(failed, batch.Failed()...)
+	}
+	if batches != 3 || succeeded != 2499 || len(failed) != 1 || failed[0].Key != "k1234" {
+		t.Errorf("batches = %d, succeeded = %d, failed = %v", batches, succeeded, failed)
+	}
+	if keys := srv.Keys("bucket"); len(keys) != 1 {
+		t.Errorf("remaining keys = %v", keys)
+	}
+}
@@ -0,0 +1,113 @@
+package oss
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 下载prefix下的所有object到localdir，返回total、skip、finish
+func (this *Client) DownloadToDir(bucket, prefix, localdir string, options map[string]string) (map[string]int, error) {
+	plan, err := this.DownloadToDirPlan(bucket, prefix, localdir, options)
+	if err != nil {
+		return nil, err
+	}
+	return this.ExecutePlan(plan, options)
+}
+
+// DownloadToDir的执行计划，本地文件保留object相对prefix所在目录的路径
+//
+// 以/结尾的目录占位object只创建本地目录，绝对路径或包含..跳出localdir的key跳过；
+// replace不为true时按compare比较(同SyncPlan，默认mtime)，本地文件未变化则跳过
+func (this *Client) DownloadToDirPlan(bucket, prefix, localdir string, options map[string]string) (*Plan, error) {
+	compare := options["compare"]
+	if compare == "" {
+		compare = "mtime"
+	}
+	if compare != "size" && compare != "mtime" && compare != "etag" && compare != "checksum" {
+		return nil, errors.New("unsupported compare: " + compare)
+	}
+	threadNum := this.threadMaxNum
+	if options["thread_num"] != "" {
+		tmpThreadNum, err := strconv.Atoi(options["thread_num"])
+		if err == nil && tmpThreadNum <= this.threadMaxNum && tmpThreadNum >= this.threadMinNum {
+			threadNum = tmpThreadNum
+		}
+	}
+	localdir = strings.TrimRight(localdir, "/") + "/"
+	filter, err := this.localPathFilter(localdir, options)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := make([]PlanAction, 0)
+	var wg sync.WaitGroup
+	var queueMaxSize = make(chan bool, threadNum)
+	var compareErr error
+	var compareErrOnce sync.Once
+	//actions[i]需要下载的原因，空为未变化
+	var reasons = map[int]string{}
+	var reasonsLock sync.Mutex
+	list, listErr := this.ListParallel(bucket, prefix, threadNum, nil)
+	for v := range list {
+		name := relativeKey(v.Key, prefix)
+		if name == "" || !filter.Match(name) {
+			continue
+		}
+		action := PlanAction{Action: PlanDownload, Bucket: bucket, Key: v.Key, LocalFile: localdir + name, Size: v.SizeInt64(), Reason: "new"}
+		if !isSafeLocalPath(name) {
+			action.Action, action.Reason, action.LocalFile = PlanSkip, "unsafe key", ""
+			actions = append(actions, action)
+			continue
+		}
+		fi, statErr := os.Stat(action.LocalFile)
+		switch {
+		case strings.HasSuffix(name, "/"):
+			action.Action, action.Reason = PlanMkdir, "directory"
+			if statErr == nil && fi.IsDir() {
+				action.Action, action.Reason = PlanSkip, "directory exists"
+			}
+		case statErr != nil:
+		case fi.IsDir():
+			action.Action, action.Reason = PlanSkip, "local path is a directory"
+		case options["replace"] == "true":
+			action.Reason = "replace"
+		default:
+			//本地文件已存在，并发比较
+			local := syncFile{path: action.LocalFile, size: fi.Size(), modTime: fi.ModTime()}
+			actions = append(actions, action)
+			wg.Add(1)
+			queueMaxSize <- true
+			go func(i int, remote ListObjectContents) {
+				defer wg.Done()
+				defer func() { <-queueMaxSize }()
+				reason, err := this.syncCompare(compare, SyncDownload, local, remote, bucket, remote.Key)
+				if err != nil {
+					compareErrOnce.Do(func() { compareErr = err })
+					return
+				}
+				reasonsLock.Lock()
+				reasons[i] = reason
+				reasonsLock.Unlock()
+			}(len(actions)-1, v.ListObjectContents)
+			continue
+		}
+		actions = append(actions, action)
+	}
+	wg.Wait()
+	if err := <-listErr; err != nil {
+		return nil, err
+	}
+	if compareErr != nil {
+		return nil, compareErr
+	}
+	for i, reason := range reasons {
+		actions[i].Reason = reason
+		if reason == "" {
+			actions[i].Action, actions[i].Reason = PlanSkip, "unchanged"
+		}
+	}
+	return &Plan{Actions: actions}, nil
+}
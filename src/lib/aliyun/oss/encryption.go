@@ -0,0 +1,59 @@
+package oss
+
+import (
+	"encoding/xml"
+	"errors"
+)
+
+type ServerSideEncryptionRule struct {
+	XMLName                            xml.Name                           `xml:"ServerSideEncryptionRule" json:"-"`
+	ApplyServerSideEncryptionByDefault ApplyServerSideEncryptionByDefault `xml:"ApplyServerSideEncryptionByDefault" json:"ApplyServerSideEncryptionByDefault"`
+}
+
+type ApplyServerSideEncryptionByDefault struct {
+	SSEAlgorithm      string `xml:"SSEAlgorithm" json:"SSEAlgorithm"`
+	KMSMasterKeyID    string `xml:"KMSMasterKeyID,omitempty" json:"KMSMasterKeyID,omitempty"`
+	KMSDataEncryption string `xml:"KMSDataEncryption,omitempty" json:"KMSDataEncryption,omitempty"`
+}
+
+func (this *Client) GetBucketEncryption(bucket string) (*ServerSideEncryptionRule, error) {
+	res, err := this.bucketRequest("GET", bucket, "?encryption", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var encryption ServerSideEncryptionRule
+	if err := xml.Unmarshal([]byte(res["Body"]), &encryption); err != nil {
+		return nil, err
+	}
+	return &encryption, nil
+}
+
+// 与对象级--sse一致：AES256、KMS、SM4，KMSMasterKeyID只用于KMS
+func (this *ServerSideEncryptionRule) Validate() error {
+	rule := this.ApplyServerSideEncryptionByDefault
+	switch rule.SSEAlgorithm {
+	case "AES256", "SM4":
+		if rule.KMSMasterKeyID != "" {
+			return errors.New("encryption: KMSMasterKeyID requires SSEAlgorithm=KMS")
+		}
+	case "KMS":
+	default:
+		return errors.New("unsupported sse: " + rule.SSEAlgorithm)
+	}
+	return nil
+}
+
+func (this *Client) PutBucketEncryption(bucket string, encryption *ServerSideEncryptionRule) (map[string]string, error) {
+	if err := encryption.Validate(); err != nil {
+		return nil, err
+	}
+	body, err := xml.Marshal(encryption)
+	if err != nil {
+		return nil, err
+	}
+	return this.bucketRequest("PUT", bucket, "?encryption", "", body, nil)
+}
+
+func (this *Client) DeleteBucketEncryption(bucket string) (map[string]string, error) {
+	return this.bucketRequest("DELETE", bucket, "?encryption", "", nil, nil)
+}
@@ -0,0 +1,210 @@
+package oss
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// 本地目录下的忽略规则文件，格式同exclude-from
+const ossIgnoreFile = ".ossignore"
+
+// rsync风格的include/exclude过滤规则，用于本地文件和object的相对路径(相对目录或前缀)
+//
+// 规则按顺序匹配，第一条匹配路径任意一级(目录或文件)的规则决定是否包含，都不匹配时包含，
+// 因此需要例外时应把包含规则写在排除规则之前。glob语法：
+//
+//	"*"    匹配除/以外的任意字符，"**"匹配包括/在内的任意字符，"?"匹配单个字符
+//	"/a"   以/开头时只匹配根目录下的路径
+//	"a/b"  包含/时匹配完整路径的末尾部分，不包含/时匹配任意一级文件名或目录名
+//	"dir/" 以/结尾时只匹配目录，目录下的所有文件都受影响
+type PathFilter struct {
+	rules []pathRule
+}
+
+type pathRule struct {
+	include bool
+	dirOnly bool
+	//正则规则只匹配完整相对路径
+	fullPath bool
+	regex    *regexp.Regexp
+}
+
+// 由options生成过滤规则，按以下顺序组合：
+//
+//	include       逗号分隔的glob
+//	include-regex 匹配完整相对路径的正则
+//	exclude       逗号分隔的glob
+//	exclude-regex 匹配完整相对路径的正则
+//	exclude-from  规则文件，每行一条："+ glob"包含，"- glob"或"glob"排除，#开头为注释，
+//	              不支持gitignore的"!glob"(其语义依赖后面的规则覆盖前面的规则)
+//	ignore-file   规则文件，格式同exclude-from，通常为本地目录下的.ossignore
+func NewPathFilter(options map[string]string) (*PathFilter, error) {
+	filter := &PathFilter{rules: make([]pathRule, 0)}
+	for _, pattern := range strings.Split(options["include"], ",") {
+		if err := filter.addGlob(true, pattern); err != nil {
+			return nil, err
+		}
+	}
+	if err := filter.addRegex(true, options["include-regex"]); err != nil {
+		return nil, err
+	}
+	for _, pattern := range strings.Split(options["exclude"], ",") {
+		if err := filter.addGlob(false, pattern); err != nil {
+			return nil, err
+		}
+	}
+	if err := filter.addRegex(false, options["exclude-regex"]); err != nil {
+		return nil, err
+	}
+	for _, file := range []string{options["exclude-from"], options["ignore-file"]} {
+		if file == "" {
+			continue
+		}
+		if err := filter.addFile(file); err != nil {
+			return nil, err
+		}
+	}
+	return filter, nil
+}
+
+// 本地目录的过滤规则：未指定ignore-file时使用目录下的.ossignore(存在时)
+func (this *Client) localPathFilter(localdir string, options map[string]string) (*PathFilter, error) {
+	if options["ignore-file"] == "" {
+		ignoreFile := filepath.Join(localdir, ossIgnoreFile)
+		if _, err := os.Stat(ignoreFile); err == nil {
+			tmpOptions := map[string]string{"ignore-file": ignoreFile}
+			for k, v := range options {
+				if k != "ignore-file" {
+					tmpOptions[k] = v
+				}
+			}
+			options = tmpOptions
+		}
+	}
+	return NewPathFilter(options)
+}
+
+// 相对路径是否被包含，filter为nil时全部包含
+func (this *PathFilter) Match(name string) bool {
+	if this == nil {
+		return true
+	}
+	name = strings.TrimLeft(filepath.ToSlash(name), "/")
+	//依次检查各级目录和文件本身
+	parts := strings.Split(name, "/")
+	for _, rule := range this.rules {
+		for i := range parts {
+			isDir := i < len(parts)-1
+			if (rule.dirOnly && !isDir) || (rule.fullPath && isDir) {
+				continue
+			}
+			if rule.regex.MatchString(strings.Join(parts[:i+1], "/")) {
+				return rule.include
+			}
+		}
+	}
+	return true
+}
+
+func (this *PathFilter) addGlob(include bool, pattern string) error {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return nil
+	}
+	glob := pattern
+	rule := pathRule{include: include}
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	expr := "(^|/)"
+	if strings.HasPrefix(pattern, "/") {
+		expr = "^"
+		pattern = strings.TrimLeft(pattern, "/")
+	}
+	regex, err := regexp.Compile(expr + globToRegexp(pattern) + "$")
+	if err != nil {
+		return fmt.Errorf("invalid pattern %q: %v", glob, err)
+	}
+	rule.regex = regex
+	this.rules = append(this.rules, rule)
+	return nil
+}
+
+// 正则规则直接匹配完整相对路径
+func (this *PathFilter) addRegex(include bool, expr string) error {
+	if expr == "" {
+		return nil
+	}
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return err
+	}
+	this.rules = append(this.rules, pathRule{include: include, fullPath: true, regex: regex})
+	return nil
+}
+
+func (this *PathFilter) addFile(file string) error {
+	fd, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	scanner := bufio.NewScanner(fd)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "+ "):
+			err = this.addGlob(true, line[2:])
+		case strings.HasPrefix(line, "- "):
+			err = this.addGlob(false, line[2:])
+		case strings.HasPrefix(line, "!"):
+			err = errors.New(`"!" rules are not supported, put "+ ` + line[1:] + `" before the exclude rule`)
+		default:
+			err = this.addGlob(false, line)
+		}
+		if err != nil {
+			return fmt.Errorf("%s:%d: %v", file, lineNum, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func globToRegexp(pattern string) string {
+	var expr strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				expr.WriteString(".*")
+				i++
+			} else {
+				expr.WriteString("[^/]*")
+			}
+		case '?':
+			expr.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				expr.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + strings.Replace(class, `\`, `\\`, -1) + "]")
+			i += end + 1
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return expr.String()
+}
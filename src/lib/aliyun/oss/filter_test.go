@@ -0,0 +1,52 @@
+package oss
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPathFilterInvalidGlob(t *testing.T) {
+	for _, pattern := range []string{"[]", "[!]", "[z-a]"} {
+		if _, err := NewPathFilter(map[string]string{"exclude": pattern}); err == nil {
+			t.Fatalf("exclude %q: expected error", pattern)
+		}
+		if _, err := NewPathFilter(map[string]string{"include": pattern}); err == nil {
+			t.Fatalf("include %q: expected error", pattern)
+		}
+	}
+}
+
+func TestPathFilterFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ossfilter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, ossIgnoreFile)
+
+	if err := ioutil.WriteFile(file, []byte("# comment\n+ keep.log\n*.log\n- tmp/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	filter, err := NewPathFilter(map[string]string{"ignore-file": file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]bool{"keep.log": true, "a/keep.log": true, "a.log": false, "tmp/a.txt": false, "a.txt": true} {
+		if filter.Match(name) != want {
+			t.Fatalf("Match(%q) = %v, want %v", name, !want, want)
+		}
+	}
+
+	for _, content := range []string{"*.log\n!keep.log\n", "[z-a]\n"} {
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := NewPathFilter(map[string]string{"exclude-from": file})
+		if err == nil || !strings.Contains(err.Error(), file+":") {
+			t.Fatalf("%q: expected error with line number, got %v", content, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package oss
+
+import (
+	"path"
+	"regexp"
+	"time"
+)
+
+// object过滤条件，零值字段不参与过滤
+type ObjectFilter struct {
+	//匹配key的最后一段，glob语法同path.Match
+	Name string
+	//匹配完整key
+	Regex *regexp.Regexp
+	//大小大于Larger、小于Smaller，单位字节
+	Larger  int64
+	Smaller int64
+	//最后修改时间早于OlderThan、晚于NewerThan
+	OlderThan time.Time
+	NewerThan time.Time
+
+	StorageClass string
+}
+
+func (this *ObjectFilter) Match(object ListObjectContents) bool {
+	if this.Name != "" {
+		matched, err := path.Match(this.Name, path.Base(object.Key))
+		if err != nil || !matched {
+			return false
+		}
+	}
+	if this.Regex != nil && !this.Regex.MatchString(object.Key) {
+		return false
+	}
+	if this.Larger > 0 || this.Smaller > 0 {
+		size := object.SizeInt64()
+		if this.Larger > 0 && size <= this.Larger {
+			return false
+		}
+		if this.Smaller > 0 && size >= this.Smaller {
+			return false
+		}
+	}
+	if !this.OlderThan.IsZero() || !this.NewerThan.IsZero() {
+		lastModified, err := time.Parse("2006-01-02T15:04:05.000Z", object.LastModified)
+		if err != nil {
+			return false
+		}
+		if !this.OlderThan.IsZero() && !lastModified.Before(this.OlderThan) {
+			return false
+		}
+		if !this.NewerThan.IsZero() && !lastModified.After(this.NewerThan) {
+			return false
+		}
+	}
+	if this.StorageClass != "" && this.StorageClass != object.StorageClass {
+		return false
+	}
+	return true
+}
+
+// 并发列举prefix下的object，返回满足filter的object，结果按key顺序，done同ListParallel
+func (this *Client) Find(bucket, prefix string, filter *ObjectFilter, workers int, done <-chan struct{}) (<-chan ObjectIteratorItem, <-chan error) {
+	objects := make(chan ObjectIteratorItem, 1000)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(objects)
+		defer close(errs)
+		list, listErr := this.ListParallel(bucket, prefix, workers, done)
+		for v := range list {
+			if filter == nil || filter.Match(v.ListObjectContents) {
+				select {
+				case objects <- v:
+				case <-done:
+				}
+			}
+		}
+		if err := <-listErr; err != nil {
+			errs <- err
+		}
+	}()
+	return objects, errs
+}
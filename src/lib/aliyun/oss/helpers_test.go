@@ -0,0 +1,22 @@
+package oss
+
+import (
+	"lib/aliyun/oss/osstest"
+)
+
+func newTestClient() (*Client, *osstest.Server) {
+	srv := osstest.NewServer()
+	return New(srv.Host, osstest.AccessKeyId, osstest.AccessKeySecret), srv
+}
+
+func planActions(plan *Plan) map[string]string {
+	actions := map[string]string{}
+	for _, v := range plan.Actions {
+		name := v.Key
+		if name == "" {
+			name = v.LocalFile
+		}
+		actions[name] = v.Action
+	}
+	return actions
+}
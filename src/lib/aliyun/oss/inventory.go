@@ -0,0 +1,106 @@
+package oss
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/url"
+)
+
+type InventoryConfiguration struct {
+	XMLName                xml.Name             `xml:"InventoryConfiguration" json:"-"`
+	Id                     string               `xml:"Id" json:"Id"`
+	IsEnabled              bool                 `xml:"IsEnabled" json:"IsEnabled"`
+	Prefix                 string               `xml:"Filter>Prefix,omitempty" json:"Prefix,omitempty"`
+	Destination            InventoryDestination `xml:"Destination>OSSBucketDestination" json:"Destination"`
+	Frequency              string               `xml:"Schedule>Frequency" json:"Frequency"`
+	IncludedObjectVersions string               `xml:"IncludedObjectVersions" json:"IncludedObjectVersions"`
+	OptionalFields         []string             `xml:"OptionalFields>Field,omitempty" json:"OptionalFields,omitempty"`
+}
+
+// Bucket格式为acs:oss:::bucket
+type InventoryDestination struct {
+	Format     string               `xml:"Format" json:"Format"`
+	AccountId  string               `xml:"AccountId" json:"AccountId"`
+	RoleArn    string               `xml:"RoleArn" json:"RoleArn"`
+	Bucket     string               `xml:"Bucket" json:"Bucket"`
+	Prefix     string               `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+	Encryption *InventoryEncryption `xml:"Encryption,omitempty" json:"Encryption,omitempty"`
+}
+
+type InventoryEncryption struct {
+	SseOss *struct{}           `xml:"SSE-OSS,omitempty" json:"SseOss,omitempty"`
+	SseKms *InventorySseKmsKey `xml:"SSE-KMS,omitempty" json:"SseKms,omitempty"`
+}
+
+type InventorySseKmsKey struct {
+	KeyId string `xml:"KeyId" json:"KeyId"`
+}
+
+type ListInventoryConfigurationsResult struct {
+	Configurations        []InventoryConfiguration `xml:"InventoryConfiguration"`
+	IsTruncated           string                   `xml:"IsTruncated"`
+	NextContinuationToken string                   `xml:"NextContinuationToken"`
+}
+
+func (this *InventoryConfiguration) Validate() error {
+	if this.Id == "" {
+		return errors.New("inventory: Id is required")
+	}
+	if this.Destination.Bucket == "" || this.Destination.AccountId == "" || this.Destination.RoleArn == "" {
+		return errors.New("inventory: Destination Bucket, AccountId and RoleArn are required")
+	}
+	if this.Destination.Format == "" {
+		this.Destination.Format = "CSV"
+	}
+	if this.Frequency != "Daily" && this.Frequency != "Weekly" {
+		return errors.New("inventory: Frequency must be Daily or Weekly")
+	}
+	if this.IncludedObjectVersions != "All" && this.IncludedObjectVersions != "Current" {
+		return errors.New("inventory: IncludedObjectVersions must be All or Current")
+	}
+	return nil
+}
+
+func (this *Client) GetBucketInventory(bucket, inventoryId string) (*InventoryConfiguration, error) {
+	res, err := this.bucketRequest("GET", bucket, "?inventory&inventoryId="+url.QueryEscape(inventoryId), "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var inventory InventoryConfiguration
+	if err := xml.Unmarshal([]byte(res["Body"]), &inventory); err != nil {
+		return nil, err
+	}
+	return &inventory, nil
+}
+
+func (this *Client) PutBucketInventory(bucket string, inventory *InventoryConfiguration) (map[string]string, error) {
+	if err := inventory.Validate(); err != nil {
+		return nil, err
+	}
+	body, err := xml.Marshal(inventory)
+	if err != nil {
+		return nil, err
+	}
+	return this.bucketRequest("PUT", bucket, "?inventory&inventoryId="+url.QueryEscape(inventory.Id), "", body, nil)
+}
+
+func (this *Client) DeleteBucketInventory(bucket, inventoryId string) (map[string]string, error) {
+	return this.bucketRequest("DELETE", bucket, "?inventory&inventoryId="+url.QueryEscape(inventoryId), "", nil, nil)
+}
+
+// 分页使用NextContinuationToken作为下次请求的continuation-token
+func (this *Client) ListBucketInventory(bucket string, options map[string]string) (*ListInventoryConfigurationsResult, error) {
+	subResource := "?inventory"
+	if options["continuation-token"] != "" {
+		subResource = "?continuation-token=" + url.QueryEscape(options["continuation-token"]) + "&inventory"
+	}
+	res, err := this.bucketRequest("GET", bucket, subResource, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var list ListInventoryConfigurationsResult
+	if err := xml.Unmarshal([]byte(res["Body"]), &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
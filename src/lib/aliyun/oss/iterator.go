@@ -0,0 +1,171 @@
+package oss
+
+import (
+	"sort"
+	"strconv"
+)
+
+// ObjectIterator按key顺序遍历ListObject结果，处理当前页时后台预取下一页
+//
+//	it := client.NewObjectIterator(bucket, map[string]string{"prefix": prefix})
+//	defer it.Close()
+//	for it.Next() {
+//		object := it.Object()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type ObjectIterator struct {
+	pages   chan *ListObjectResult
+	errs    chan error
+	closing chan struct{}
+	closed  bool
+
+	items  []ObjectIteratorItem
+	index  int
+	err    error
+	done   bool
+	object ObjectIteratorItem
+}
+
+// 有delimiter时，目录(CommonPrefixes)以IsPrefix为true的项返回，Key为目录前缀
+type ObjectIteratorItem struct {
+	ListObjectContents
+	IsPrefix bool
+}
+
+// options同ListObject：prefix、delimiter、marker、max-keys
+func (this *Client) NewObjectIterator(bucket string, options map[string]string) *ObjectIterator {
+	it := &ObjectIterator{
+		pages:   make(chan *ListObjectResult, 1),
+		errs:    make(chan error, 1),
+		closing: make(chan struct{}),
+	}
+	listOptions := map[string]string{"max-keys": "1000"}
+	for k, v := range options {
+		if v != "" {
+			listOptions[k] = v
+		}
+	}
+	go this.prefetchObjects(bucket, listOptions, it)
+	return it
+}
+
+func (this *Client) prefetchObjects(bucket string, options map[string]string, it *ObjectIterator) {
+	defer close(it.pages)
+	for {
+		var list *ListObjectResult
+		var err error
+		for i := 0; i < this.maxRetryNum; i++ {
+			list, err = this.ListObject(bucket, options)
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			it.errs <- err
+			return
+		}
+		select {
+		case it.pages <- list:
+		case <-it.closing:
+			return
+		}
+		if list.IsTruncated != "true" {
+			return
+		}
+		marker := list.NextMarker
+		if marker == "" && len(list.Contents) > 0 {
+			marker = list.Contents[len(list.Contents)-1].Key
+		}
+		if marker == "" {
+			return
+		}
+		options["marker"] = marker
+	}
+}
+
+func (this *ObjectIterator) Next() bool {
+	for this.index >= len(this.items) {
+		if this.done {
+			return false
+		}
+		list, ok := <-this.pages
+		if !ok {
+			this.done = true
+			select {
+			case this.err = <-this.errs:
+			default:
+			}
+			return false
+		}
+		this.items = pageItems(list)
+		this.index = 0
+	}
+	this.object = this.items[this.index]
+	this.index++
+	return true
+}
+
+func (this *ObjectIterator) Object() ObjectIteratorItem {
+	return this.object
+}
+
+func (this *ObjectIterator) Err() error {
+	return this.err
+}
+
+// 提前结束遍历时调用，停止后台预取并等待其退出(最多等待正在进行的一次列举请求)
+func (this *ObjectIterator) Close() {
+	if !this.closed {
+		this.closed = true
+		close(this.closing)
+		for range this.pages {
+		}
+	}
+}
+
+// 按key顺序合并object和目录
+func pageItems(list *ListObjectResult) []ObjectIteratorItem {
+	items := make([]ObjectIteratorItem, 0, len(list.Contents)+len(list.CommonPrefixes))
+	for _, v := range list.Contents {
+		items = append(items, ObjectIteratorItem{ListObjectContents: v})
+	}
+	for _, v := range list.CommonPrefixes {
+		items = append(items, ObjectIteratorItem{ListObjectContents: ListObjectContents{Key: v.Prefix}, IsPrefix: true})
+	}
+	if len(list.CommonPrefixes) > 0 {
+		sort.Slice(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+	}
+	return items
+}
+
+// channel版本：objects遍历结束后关闭，出错时errs收到一个错误
+//
+// 调用方提前结束读取时关闭done，后台列举停止后关闭objects和errs；done为nil时不能取消
+func (this *Client) ListObjectChannel(bucket string, options map[string]string, done <-chan struct{}) (<-chan ObjectIteratorItem, <-chan error) {
+	objects := make(chan ObjectIteratorItem, 1000)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(objects)
+		it := this.NewObjectIterator(bucket, options)
+		defer it.Close()
+		for it.Next() {
+			select {
+			case objects <- it.Object():
+			case <-done:
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			errs <- err
+		}
+	}()
+	return objects, errs
+}
+
+// Size转为int64，解析失败返回0
+func (this ListObjectContents) SizeInt64() int64 {
+	size, _ := strconv.ParseInt(this.Size, 10, 64)
+	return size
+}
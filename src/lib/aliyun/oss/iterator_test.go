@@ -0,0 +1,57 @@
+package oss
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestListObjectChannel(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	for i := 0; i < 2500; i++ {
+		srv.PutObject("bucket", fmt.Sprintf("k%04d", i), []byte("x"), nil)
+	}
+
+	objects, errs := client.ListObjectChannel("bucket", map[string]string{}, nil)
+	count := 0
+	for range objects {
+		count++
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	if count != 2500 {
+		t.Fatalf("listed %d objects, want 2500", count)
+	}
+
+	//读取一个后取消，objects和errs应当关闭且不再继续列举
+	done := make(chan struct{})
+	objects, errs = client.ListObjectChannel("bucket", map[string]string{"max-keys": "100"}, done)
+	<-objects
+	close(done)
+	timeout := time.After(5 * time.Second)
+	for closed := false; !closed; {
+		select {
+		case _, ok := <-objects:
+			closed = !ok
+		case <-timeout:
+			t.Fatal("objects not closed after cancel")
+		}
+	}
+	select {
+	case <-errs:
+	case <-timeout:
+		t.Fatal("errs not closed after cancel")
+	}
+	lists := 0
+	for _, r := range srv.Requests() {
+		if r.Method == "GET" && r.Key == "" {
+			lists++
+		}
+	}
+	//第一次完整列举3页，取消的列举最多预取到缓冲区满
+	if lists >= 3+25 {
+		t.Fatalf("listing continued after cancel: %d list requests", lists)
+	}
+}
@@ -0,0 +1,116 @@
+package oss
+
+import (
+	"encoding/xml"
+	"errors"
+	"strconv"
+)
+
+type LifecycleConfiguration struct {
+	XMLName xml.Name        `xml:"LifecycleConfiguration" json:"-"`
+	Rules   []LifecycleRule `xml:"Rule" json:"Rules"`
+}
+
+type LifecycleRule struct {
+	ID                           string                                 `xml:"ID,omitempty" json:"ID,omitempty"`
+	Prefix                       string                                 `xml:"Prefix" json:"Prefix"`
+	Status                       string                                 `xml:"Status" json:"Status"`
+	Tags                         []Tag                                  `xml:"Tag,omitempty" json:"Tags,omitempty"`
+	Expiration                   *LifecycleExpiration                   `xml:"Expiration,omitempty" json:"Expiration,omitempty"`
+	Transitions                  []LifecycleTransition                  `xml:"Transition,omitempty" json:"Transitions,omitempty"`
+	AbortMultipartUpload         *LifecycleAbortMultipartUpload         `xml:"AbortMultipartUpload,omitempty" json:"AbortMultipartUpload,omitempty"`
+	NoncurrentVersionExpiration  *LifecycleNoncurrentVersionExpiration  `xml:"NoncurrentVersionExpiration,omitempty" json:"NoncurrentVersionExpiration,omitempty"`
+	NoncurrentVersionTransitions []LifecycleNoncurrentVersionTransition `xml:"NoncurrentVersionTransition,omitempty" json:"NoncurrentVersionTransitions,omitempty"`
+}
+
+type Tag struct {
+	Key   string `xml:"Key" json:"Key"`
+	Value string `xml:"Value" json:"Value"`
+}
+
+// Days和CreatedBeforeDate二选一，ExpiredObjectDeleteMarker用于清理过期删除标记
+type LifecycleExpiration struct {
+	Days                      int    `xml:"Days,omitempty" json:"Days,omitempty"`
+	CreatedBeforeDate         string `xml:"CreatedBeforeDate,omitempty" json:"CreatedBeforeDate,omitempty"`
+	ExpiredObjectDeleteMarker *bool  `xml:"ExpiredObjectDeleteMarker,omitempty" json:"ExpiredObjectDeleteMarker,omitempty"`
+}
+
+type LifecycleTransition struct {
+	Days              int    `xml:"Days,omitempty" json:"Days,omitempty"`
+	CreatedBeforeDate string `xml:"CreatedBeforeDate,omitempty" json:"CreatedBeforeDate,omitempty"`
+	StorageClass      string `xml:"StorageClass" json:"StorageClass"`
+}
+
+type LifecycleAbortMultipartUpload struct {
+	Days              int    `xml:"Days,omitempty" json:"Days,omitempty"`
+	CreatedBeforeDate string `xml:"CreatedBeforeDate,omitempty" json:"CreatedBeforeDate,omitempty"`
+}
+
+type LifecycleNoncurrentVersionExpiration struct {
+	NoncurrentDays int `xml:"NoncurrentDays" json:"NoncurrentDays"`
+}
+
+type LifecycleNoncurrentVersionTransition struct {
+	NoncurrentDays int    `xml:"NoncurrentDays" json:"NoncurrentDays"`
+	StorageClass   string `xml:"StorageClass" json:"StorageClass"`
+}
+
+func (this *LifecycleConfiguration) Validate() error {
+	if len(this.Rules) == 0 {
+		return errors.New("lifecycle: no rules")
+	}
+	for i, rule := range this.Rules {
+		name := rule.ID
+		if name == "" {
+			name = "#" + strconv.Itoa(i+1)
+		}
+		if rule.Status != "Enabled" && rule.Status != "Disabled" {
+			return errors.New("lifecycle rule " + name + ": Status must be Enabled or Disabled")
+		}
+		if rule.Expiration == nil && len(rule.Transitions) == 0 && rule.AbortMultipartUpload == nil &&
+			rule.NoncurrentVersionExpiration == nil && len(rule.NoncurrentVersionTransitions) == 0 {
+			return errors.New("lifecycle rule " + name + ": no action")
+		}
+		if rule.Expiration != nil && rule.Expiration.Days > 0 && rule.Expiration.CreatedBeforeDate != "" {
+			return errors.New("lifecycle rule " + name + ": Expiration Days and CreatedBeforeDate are exclusive")
+		}
+		for _, transition := range rule.Transitions {
+			if transition.StorageClass == "" {
+				return errors.New("lifecycle rule " + name + ": Transition StorageClass is required")
+			}
+		}
+		for _, transition := range rule.NoncurrentVersionTransitions {
+			if transition.StorageClass == "" {
+				return errors.New("lifecycle rule " + name + ": NoncurrentVersionTransition StorageClass is required")
+			}
+		}
+	}
+	return nil
+}
+
+func (this *Client) GetBucketLifecycle(bucket string) (*LifecycleConfiguration, error) {
+	res, err := this.bucketRequest("GET", bucket, "?lifecycle", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var lifecycle LifecycleConfiguration
+	if err := xml.Unmarshal([]byte(res["Body"]), &lifecycle); err != nil {
+		return nil, err
+	}
+	return &lifecycle, nil
+}
+
+func (this *Client) PutBucketLifecycle(bucket string, lifecycle *LifecycleConfiguration) (map[string]string, error) {
+	if err := lifecycle.Validate(); err != nil {
+		return nil, err
+	}
+	body, err := xml.Marshal(lifecycle)
+	if err != nil {
+		return nil, err
+	}
+	return this.bucketRequest("PUT", bucket, "?lifecycle", "", body, nil)
+}
+
+func (this *Client) DeleteBucketLifecycle(bucket string) (map[string]string, error) {
+	return this.bucketRequest("DELETE", bucket, "?lifecycle", "", nil, nil)
+}
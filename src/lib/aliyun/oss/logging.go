@@ -0,0 +1,55 @@
+package oss
+
+import (
+	"encoding/xml"
+	"errors"
+)
+
+type BucketLoggingStatus struct {
+	XMLName        xml.Name              `xml:"BucketLoggingStatus" json:"-"`
+	LoggingEnabled *BucketLoggingEnabled `xml:"LoggingEnabled,omitempty" json:"LoggingEnabled,omitempty"`
+}
+
+type BucketLoggingEnabled struct {
+	TargetBucket string `xml:"TargetBucket" json:"TargetBucket"`
+	TargetPrefix string `xml:"TargetPrefix,omitempty" json:"TargetPrefix,omitempty"`
+}
+
+func (this *Client) GetBucketLogging(bucket string) (*BucketLoggingStatus, error) {
+	res, err := this.bucketRequest("GET", bucket, "?logging", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var logging BucketLoggingStatus
+	if err := xml.Unmarshal([]byte(res["Body"]), &logging); err != nil {
+		return nil, err
+	}
+	return &logging, nil
+}
+
+// LoggingEnabled为空时关闭日志
+// LoggingEnabled为nil时表示关闭日志
+func (this *BucketLoggingStatus) Validate() error {
+	if this.LoggingEnabled != nil && this.LoggingEnabled.TargetBucket == "" {
+		return errors.New("logging: TargetBucket is required")
+	}
+	return nil
+}
+
+func (this *Client) PutBucketLogging(bucket string, logging *BucketLoggingStatus) (map[string]string, error) {
+	if logging.LoggingEnabled == nil {
+		return this.DeleteBucketLogging(bucket)
+	}
+	if err := logging.Validate(); err != nil {
+		return nil, err
+	}
+	body, err := xml.Marshal(logging)
+	if err != nil {
+		return nil, err
+	}
+	return this.bucketRequest("PUT", bucket, "?logging", "", body, nil)
+}
+
+func (this *Client) DeleteBucketLogging(bucket string) (map[string]string, error) {
+	return this.bucketRequest("DELETE", bucket, "?logging", "", nil, nil)
+}
@@ -0,0 +1,353 @@
+package oss
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	ManifestOK   = "ok"
+	ManifestFail = "fail"
+)
+
+// 超过该大小的本地文件使用UploadLargeFile分片上传
+const uploadLargeThreshold = 100 * 1024 * 1024
+
+// 传输清单中的一项，src、dst为oss://bucket/object或本地路径：
+// oss到oss为复制，本地到oss为上传，oss到本地为下载，dst以/结尾时使用src的文件名
+//
+// headers支持x-oss-meta-*、x-oss-server-side-encryption、x-oss-server-side-encryption-key-id和disposition，
+// headers、storage_class、tags只用于复制和上传
+type ManifestEntry struct {
+	Src          string            `json:"src"`
+	Dst          string            `json:"dst"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	StorageClass string            `json:"storage_class,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+}
+
+// 清单的执行结果，按JSON输出时每行可直接作为清单重新执行，status为ok的项不再执行
+type ManifestResult struct {
+	ManifestEntry
+	Action string `json:"action,omitempty"`
+	Status string `json:"status,omitempty"`
+	ETag   string `json:"etag,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// 读取传输清单，format为jsonl(每行一个JSON对象，默认)或csv
+//
+// csv的列为src,dst,storage_class,headers,tags，headers和tags为k1=v1&k2=v2格式，第一列为src的行作为表头跳过
+func ReadManifest(r io.Reader, format string) ([]ManifestResult, error) {
+	entries := make([]ManifestResult, 0)
+	switch format {
+	case "", "jsonl", "json":
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for lineNum := 1; scanner.Scan(); lineNum++ {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var entry ManifestResult
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				return nil, fmt.Errorf("manifest line %d: %s", lineNum, err)
+			}
+			if err := entry.validate(); err != nil {
+				return nil, fmt.Errorf("manifest line %d: %s", lineNum, err)
+			}
+			entries = append(entries, entry)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	case "csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		for lineNum := 1; ; lineNum++ {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if len(record) == 0 || (lineNum == 1 && record[0] == "src") {
+				continue
+			}
+			entry, err := parseManifestRecord(record)
+			if err == nil {
+				err = entry.validate()
+			}
+			if err != nil {
+				return nil, fmt.Errorf("manifest line %d: %s", lineNum, err)
+			}
+			entries = append(entries, *entry)
+		}
+	default:
+		return nil, errors.New("unsupported manifest format: " + format)
+	}
+	return entries, nil
+}
+
+func parseManifestRecord(record []string) (*ManifestResult, error) {
+	field := func(i int) string {
+		if i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	entry := &ManifestResult{ManifestEntry: ManifestEntry{Src: field(0), Dst: field(1), StorageClass: field(2)}}
+	for i, dst := range []*map[string]string{&entry.Headers, &entry.Tags} {
+		if field(3+i) == "" {
+			continue
+		}
+		values, err := url.ParseQuery(field(3 + i))
+		if err != nil {
+			return nil, err
+		}
+		*dst = map[string]string{}
+		for k := range values {
+			(*dst)[k] = values.Get(k)
+		}
+	}
+	return entry, nil
+}
+
+func (this *ManifestResult) validate() error {
+	if this.Src == "" || this.Dst == "" {
+		return errors.New("src and dst are required")
+	}
+	srcOSS, dstOSS := strings.HasPrefix(this.Src, "oss://"), strings.HasPrefix(this.Dst, "oss://")
+	switch {
+	case srcOSS && dstOSS:
+		this.Action = PlanCopy
+	case dstOSS:
+		this.Action = PlanUpload
+	case srcOSS:
+		this.Action = PlanDownload
+	default:
+		return errors.New("src or dst must be oss://bucket/object")
+	}
+	_, err := this.options(map[string]string{})
+	return err
+}
+
+// 清单项的headers、storage_class、tags转换为Put、Copy等使用的options，未指定加密时使用defaults中的sse、sse-key-id
+func (this *ManifestEntry) options(defaults map[string]string) (map[string]string, error) {
+	options := map[string]string{
+		"sse":        defaults["sse"],
+		"sse-key-id": defaults["sse-key-id"],
+	}
+	for k, v := range this.Headers {
+		name := strings.ToLower(k)
+		switch {
+		case strings.HasPrefix(name, "x-oss-meta-"):
+			options[name] = v
+		case name == "x-oss-server-side-encryption":
+			options["sse"] = v
+		case name == "x-oss-server-side-encryption-key-id":
+			options["sse-key-id"] = v
+		case name == "disposition":
+			options["disposition"] = v
+		default:
+			return nil, errors.New("unsupported header: " + k)
+		}
+	}
+	options["x-oss-storage-class"] = this.StorageClass
+	if len(this.Tags) > 0 {
+		tags := url.Values{}
+		for k, v := range this.Tags {
+			tags.Set(k, v)
+		}
+		options["x-oss-tagging"] = tags.Encode()
+	}
+	return options, nil
+}
+
+// 并发执行清单，每完成一项从返回的channel输出结果，全部完成后关闭channel
+//
+// status为ok的项直接输出不再执行；超过1GB的复制和超过100MB的上传在其他项完成后逐个分片执行，
+// 失败的项与其他项一样最多重试maxRetryNum次。
+// options：thread_num、sse、sse-key-id
+func (this *Client) ExecuteManifest(entries []ManifestResult, options map[string]string) <-chan ManifestResult {
+	threadNum := this.threadMaxNum
+	if options["thread_num"] != "" {
+		tmpThreadNum, err := strconv.Atoi(options["thread_num"])
+		if err == nil && tmpThreadNum <= this.threadMaxNum && tmpThreadNum >= this.threadMinNum {
+			threadNum = tmpThreadNum
+		}
+	}
+	results := make(chan ManifestResult, threadNum)
+	go func() {
+		var wg sync.WaitGroup
+		var queueMaxSize = make(chan bool, threadNum)
+		largeEntries := make([]ManifestResult, 0)
+		var largeEntriesLock sync.Mutex
+		for _, entry := range entries {
+			if entry.Status == ManifestOK {
+				results <- entry
+				continue
+			}
+			wg.Add(1)
+			queueMaxSize <- true
+			go func(entry ManifestResult) {
+				defer wg.Done()
+				defer func() { <-queueMaxSize }()
+				var large bool
+				var err error
+				for i := 0; i < this.maxRetryNum; i++ {
+					if large, err = this.executeManifestEntry(&entry, options, false); err == nil || large {
+						break
+					}
+				}
+				if large {
+					largeEntriesLock.Lock()
+					largeEntries = append(largeEntries, entry)
+					largeEntriesLock.Unlock()
+					return
+				}
+				results <- manifestResult(entry, err)
+			}(entry)
+		}
+		wg.Wait()
+		//分片复制、上传会修改client的并发数和分片大小，逐个执行，分片本身是并发的
+		for _, entry := range largeEntries {
+			var err error
+			for i := 0; i < this.maxRetryNum; i++ {
+				if _, err = this.executeManifestEntry(&entry, options, true); err == nil {
+					break
+				}
+			}
+			results <- manifestResult(entry, err)
+		}
+		close(results)
+	}()
+	return results
+}
+
+func manifestResult(entry ManifestResult, err error) ManifestResult {
+	entry.Status, entry.Error = ManifestOK, ""
+	if err != nil {
+		entry.Status, entry.Error, entry.ETag = ManifestFail, err.Error(), ""
+	}
+	return entry
+}
+
+// 执行一项，large为false时遇到大文件不执行，返回large为true
+func (this *Client) executeManifestEntry(entry *ManifestResult, defaults map[string]string, large bool) (bool, error) {
+	if err := entry.validate(); err != nil {
+		return false, err
+	}
+	options, err := entry.options(defaults)
+	if err != nil {
+		return false, err
+	}
+	switch entry.Action {
+	case PlanCopy:
+		sourceBucket, sourceObject := manifestBucketObject(entry.Src)
+		bucket, object := manifestBucketObject(entry.Dst)
+		if object == "" || strings.HasSuffix(object, "/") {
+			object += path.Base(sourceObject)
+		}
+		source := "/" + sourceBucket + "/" + sourceObject
+		sourceHead, err := this.Head(sourceBucket, sourceObject)
+		if err = this.checkStatus(sourceHead, err, "200"); err != nil {
+			return false, err
+		}
+		if (ListObjectContents{Size: sourceHead["Content-Length"]}).SizeInt64() > copyLargeThreshold {
+			if !large {
+				return true, nil
+			}
+			options["thread_num"] = defaults["thread_num"]
+			res, err := this.CopyLargeFile(bucket, object, source, options)
+			if err != nil {
+				return true, err
+			}
+			entry.ETag = res["ETag"]
+			return true, nil
+		}
+		res, err := this.Copy(bucket, object, source, options)
+		if err = this.checkStatus(res, err, "200"); err != nil {
+			return false, err
+		}
+		var copyResult struct {
+			ETag string `xml:"ETag"`
+		}
+		if err := xml.Unmarshal([]byte(res["Body"]), &copyResult); err != nil {
+			return false, err
+		}
+		entry.ETag = copyResult.ETag
+	case PlanUpload:
+		bucket, object := manifestBucketObject(entry.Dst)
+		if object == "" || strings.HasSuffix(object, "/") {
+			object += filepath.Base(entry.Src)
+		}
+		fi, err := os.Stat(entry.Src)
+		if err != nil {
+			return false, err
+		}
+		if fi.Size() > uploadLargeThreshold {
+			if !large {
+				return true, nil
+			}
+			options["thread_num"] = defaults["thread_num"]
+			res, err := this.UploadLargeFile(entry.Src, bucket, object, options)
+			if err != nil {
+				return true, err
+			}
+			entry.ETag = res["ETag"]
+			return true, nil
+		}
+		body, err := ioutil.ReadFile(entry.Src)
+		if err != nil {
+			return false, err
+		}
+		res, err := this.Put(body, bucket, object, options)
+		if err = this.checkStatus(res, err, "200"); err != nil {
+			return false, err
+		}
+		entry.ETag = res["ETag"]
+	case PlanDownload:
+		bucket, object := manifestBucketObject(entry.Src)
+		localfile := entry.Dst
+		if fi, err := os.Stat(localfile); strings.HasSuffix(localfile, "/") || (err == nil && fi.IsDir()) {
+			localfile = filepath.Join(localfile, path.Base(object))
+		}
+		objectHead, err := this.Head(bucket, object)
+		if err = this.checkStatus(objectHead, err, "200"); err != nil {
+			return false, err
+		}
+		if err := os.MkdirAll(filepath.Dir(localfile), 0755); err != nil {
+			return false, err
+		}
+		size := ListObjectContents{Size: objectHead["Content-Length"]}.SizeInt64()
+		if err := this.downloadFile(bucket, object, localfile, size); err != nil {
+			return false, err
+		}
+		entry.ETag = objectHead["Etag"]
+	}
+	return false, nil
+}
+
+// oss://bucket/object拆分为bucket和object
+func manifestBucketObject(name string) (string, string) {
+	name = strings.TrimPrefix(name, "oss://")
+	if i := strings.Index(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
@@ -0,0 +1,51 @@
+package oss
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"lib/aliyun/oss/osstest"
+)
+
+func TestExecuteManifestRetriesLargeEntries(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	dir, err := ioutil.TempDir("", "ossmanifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	localfile := filepath.Join(dir, "large.bin")
+	fd, err := os.Create(localfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fd.Truncate(uploadLargeThreshold + 1); err != nil {
+		t.Fatal(err)
+	}
+	fd.Close()
+
+	//第一次完成分片上传时请求签名失败
+	completes := 0
+	srv.Hook = func(r *osstest.Request) {
+		if r.Method == "POST" && r.Query["uploadId"] != nil {
+			if completes++; completes == 1 {
+				r.Header.Del("Authorization")
+			}
+		}
+	}
+	entries := []ManifestResult{{ManifestEntry: ManifestEntry{Src: localfile, Dst: "oss://bucket/large.bin"}}}
+	for result := range client.ExecuteManifest(entries, map[string]string{}) {
+		if result.Status != ManifestOK {
+			t.Fatalf("status = %s, error = %s", result.Status, result.Error)
+		}
+	}
+	if completes != 2 {
+		t.Fatalf("complete requests = %d, want 2", completes)
+	}
+	if object, ok := srv.Object("bucket", "large.bin"); !ok || len(object.Data) != uploadLargeThreshold+1 {
+		t.Fatal("large.bin not uploaded")
+	}
+}
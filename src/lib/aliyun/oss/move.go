@@ -0,0 +1,184 @@
+package oss
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 移动(重命名)object：复制到目标，校验大小和ETag/CRC64一致后删除源object
+//
+// source为/bucket/object，超过copyLargeThreshold的object使用CopyLargeFile分片复制，
+// options同Copy，另支持thread_num(分片复制并发数)
+func (this *Client) Move(bucket, object, source string, options map[string]string) (map[string]string, error) {
+	tmpSourceInfo := strings.Split(source, "/")
+	if len(tmpSourceInfo) < 3 {
+		return nil, errors.New("invalid move source: " + source)
+	}
+	sourceBucket := tmpSourceInfo[1]
+	sourceObject := strings.Join(tmpSourceInfo[2:], "/")
+	if sourceBucket == bucket && sourceObject == object {
+		return nil, errors.New("move source and target are the same: " + source)
+	}
+	sourceHead, err := this.Head(sourceBucket, sourceObject)
+	if err != nil {
+		return nil, err
+	}
+	if sourceHead["StatusCode"] != "200" {
+		return nil, errors.New("StatusCode:" + sourceHead["StatusCode"])
+	}
+
+	isLarge := ListObjectContents{Size: sourceHead["Content-Length"]}.SizeInt64() > copyLargeThreshold
+	if isLarge {
+		_, err = this.CopyLargeFile(bucket, object, source, map[string]string{
+			"disposition": options["disposition"],
+			"thread_num":  options["thread_num"],
+			"sse":         options["sse"],
+			"sse-key-id":  options["sse-key-id"],
+		})
+	} else {
+		var res map[string]string
+		res, err = this.Copy(bucket, object, source, map[string]string{
+			"disposition": options["disposition"],
+			"sse":         options["sse"],
+			"sse-key-id":  options["sse-key-id"],
+		})
+		err = this.checkStatus(res, err, "200")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	objectHead, err := this.Head(bucket, object)
+	if err != nil {
+		return nil, err
+	}
+	if objectHead["StatusCode"] != "200" {
+		return nil, errors.New("StatusCode:" + objectHead["StatusCode"])
+	}
+	if err := sameContent(sourceHead, objectHead, !isLarge); err != nil {
+		return nil, fmt.Errorf("move %s verify fail: %s", source, err)
+	}
+
+	res, err := this.Delete(sourceBucket, sourceObject)
+	if err = this.checkStatus(res, err, "204"); err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"Bucket": bucket,
+		"Key":    object,
+		"Source": source,
+		"ETag":   objectHead["Etag"],
+		"Size":   objectHead["Content-Length"],
+	}, nil
+}
+
+// 比较两个Head结果的大小、CRC64、Content-Type和x-oss-meta-*，分片复制后ETag会变化，checkETag为false时不比较ETag
+func sameContent(sourceHead, objectHead map[string]string, checkETag bool) error {
+	if sourceHead["Content-Length"] != objectHead["Content-Length"] {
+		return errors.New("size differs")
+	}
+	if sourceHead["Content-Type"] != objectHead["Content-Type"] {
+		return errors.New("content-type differs")
+	}
+	for _, head := range []map[string]string{sourceHead, objectHead} {
+		for k := range head {
+			if strings.HasPrefix(strings.ToLower(k), "x-oss-meta-") && sourceHead[k] != objectHead[k] {
+				return errors.New(strings.ToLower(k) + " differs")
+			}
+		}
+	}
+	sourceCRC, objectCRC := sourceHead["X-Oss-Hash-Crc64ecma"], objectHead["X-Oss-Hash-Crc64ecma"]
+	if sourceCRC != "" && objectCRC != "" && sourceCRC != objectCRC {
+		return errors.New("crc64 differs")
+	}
+	if checkETag && sourceHead["Etag"] != objectHead["Etag"] {
+		return errors.New("etag differs")
+	}
+	return nil
+}
+
+// 移动source(/bucket/prefix)下的所有object到bucket/prefix，prefix按目录处理，返回total、skip、finish
+func (this *Client) MovePrefix(bucket, prefix, source string, options map[string]string) (map[string]int, error) {
+	plan, err := this.MovePrefixPlan(bucket, prefix, source, options)
+	if err != nil {
+		return nil, err
+	}
+	return this.ExecutePlan(plan, options)
+}
+
+// MovePrefix的执行计划，目标object保留相对源前缀所在目录的路径，过滤规则同NewPathFilter
+//
+// 中断后重新执行即可继续：已移动的object不再出现在源前缀下；
+// 已复制但未删除源的object(目标大小、Content-Type、x-oss-meta-*和ETag一致，分片复制的比较CRC64)只删除源object
+func (this *Client) MovePrefixPlan(bucket, prefix, source string, options map[string]string) (*Plan, error) {
+	tmpSourceInfo := strings.Split(source, "/")
+	if len(tmpSourceInfo) < 2 {
+		return nil, errors.New("invalid move source: " + source)
+	}
+	sourceBucket := tmpSourceInfo[1]
+	sourcePrefix := strings.Join(tmpSourceInfo[2:], "/")
+	//源前缀按目录处理，photos不包含photos-archive/下的object
+	if sourcePrefix != "" && !strings.HasSuffix(sourcePrefix, "/") {
+		sourcePrefix += "/"
+	}
+	objectPrefix := strings.TrimLeft(strings.TrimRight(prefix, "/")+"/", "/")
+	if sourceBucket == bucket && strings.HasPrefix(objectPrefix, sourcePrefix) {
+		return nil, errors.New("move target prefix is inside source prefix: " + objectPrefix)
+	}
+	filter, err := NewPathFilter(options)
+	if err != nil {
+		return nil, err
+	}
+	targetObjects, err := this.listObjectMap(bucket, objectPrefix)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{Actions: make([]PlanAction, 0)}
+	list, listErr := this.ListParallel(sourceBucket, sourcePrefix, this.threadMaxNum, nil)
+	for v := range list {
+		relative := relativeKey(v.Key, sourcePrefix)
+		if !filter.Match(relative) {
+			continue
+		}
+		action := PlanAction{
+			Action: PlanMove,
+			Bucket: bucket,
+			Key:    objectPrefix + relative,
+			Source: "/" + sourceBucket + "/" + v.Key,
+			Size:   v.SizeInt64(),
+			Reason: "new",
+		}
+		if target, ok := targetObjects[action.Key]; ok {
+			action.Reason = "replace"
+			if this.alreadyCopied(sourceBucket, v.ListObjectContents, bucket, target) {
+				action = PlanAction{Action: PlanDelete, Bucket: sourceBucket, Key: v.Key, Size: v.SizeInt64(), Reason: "already copied"}
+			}
+		}
+		plan.add(action)
+	}
+	if err := <-listErr; err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// 目标与源内容一致时返回true，比较规则同Move的校验
+func (this *Client) alreadyCopied(sourceBucket string, source ListObjectContents, bucket string, target ListObjectContents) bool {
+	if source.Size != target.Size {
+		return false
+	}
+	sourceHead, err := this.Head(sourceBucket, source.Key)
+	if err != nil || sourceHead["StatusCode"] != "200" {
+		return false
+	}
+	objectHead, err := this.Head(bucket, target.Key)
+	if err != nil || objectHead["StatusCode"] != "200" {
+		return false
+	}
+	if sourceHead["Etag"] == objectHead["Etag"] {
+		return sameContent(sourceHead, objectHead, true) == nil
+	}
+	//分片复制的ETag不同，比较CRC64
+	return sourceHead["X-Oss-Hash-Crc64ecma"] != "" && sameContent(sourceHead, objectHead, false) == nil
+}
@@ -0,0 +1,146 @@
+package oss
+
+import (
+	"testing"
+
+	"lib/aliyun/oss/osstest"
+)
+
+func TestCopyLargeFileKeepsMetadata(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	srv.PutObject("bucket", "src.bin", []byte("large object"), map[string]string{
+		"Content-Type":        "image/png",
+		"Content-Disposition": "inline",
+		"Cache-Control":       "no-cache",
+		"X-Oss-Meta-Owner":    "alice",
+	})
+
+	if _, err := client.CopyLargeFile("bucket", "dst.dat", "/bucket/src.bin", map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	object, _ := srv.Object("bucket", "dst.dat")
+	for k, want := range map[string]string{"Content-Type": "image/png", "Content-Disposition": "inline", "Cache-Control": "no-cache", "X-Oss-Meta-Owner": "alice"} {
+		if object.Headers[k] != want {
+			t.Fatalf("%s = %q, want %q", k, object.Headers[k], want)
+		}
+	}
+
+	//指定元数据时替换源object的元数据，Content-Type仍保留
+	if _, err := client.CopyLargeFile("bucket", "dst2.dat", "/bucket/src.bin", map[string]string{"x-oss-meta-team": "oss"}); err != nil {
+		t.Fatal(err)
+	}
+	object, _ = srv.Object("bucket", "dst2.dat")
+	if object.Headers["Content-Type"] != "image/png" || object.Headers["X-Oss-Meta-Team"] != "oss" || object.Headers["X-Oss-Meta-Owner"] != "" {
+		t.Fatalf("unexpected headers %v", object.Headers)
+	}
+
+	srcHead, err := client.Head("bucket", "src.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dstHead, err := client.Head("bucket", "dst2.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sameContent(srcHead, dstHead, false) == nil {
+		t.Fatal("sameContent ignored differing x-oss-meta-*")
+	}
+}
+
+func TestMovePrefixPlanTarget(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	for _, key := range []string{"photos/a.jpg", "photos/b/c.jpg", "photos-archive/old.jpg"} {
+		srv.PutObject("bucket", key, []byte(key), nil)
+	}
+
+	plan, err := client.MovePrefixPlan("bucket", "photos-archive/", "/bucket/photos", map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := planActions(plan)
+	if len(actions) != 2 || actions["photos-archive/a.jpg"] != PlanMove || actions["photos-archive/b/c.jpg"] != PlanMove {
+		t.Fatalf("unexpected plan %v", actions)
+	}
+
+	for _, source := range []string{"/bucket/photos", "/bucket/photos/", "/bucket/"} {
+		if _, err := client.MovePrefixPlan("bucket", "photos/b/", source, map[string]string{}); err == nil {
+			t.Fatalf("%s to photos/b/: expected error", source)
+		}
+	}
+}
+
+func TestMoveVerifyThenDelete(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	meta := map[string]string{"Content-Type": "image/png", "X-Oss-Meta-Owner": "alice"}
+	srv.PutObject("bucket", "src.png", []byte("image"), meta)
+
+	if _, err := client.Move("bucket", "dst.png", "/bucket/src.png", map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := srv.Object("bucket", "src.png"); ok {
+		t.Fatal("source not deleted")
+	}
+	object, ok := srv.Object("bucket", "dst.png")
+	if !ok || string(object.Data) != "image" || object.Headers["Content-Type"] != "image/png" || object.Headers["X-Oss-Meta-Owner"] != "alice" {
+		t.Fatal("target differs from source")
+	}
+
+	//复制后校验前目标被覆盖，校验失败时保留源object
+	srv.PutObject("bucket", "src.png", []byte("image"), meta)
+	srv.Hook = func(r *osstest.Request) {
+		if r.Method == "HEAD" && r.Key == "dst2.png" {
+			srv.PutObject("bucket", "dst2.png", []byte("other"), meta)
+		}
+	}
+	if _, err := client.Move("bucket", "dst2.png", "/bucket/src.png", map[string]string{}); err == nil {
+		t.Fatal("expected verify error")
+	}
+	if _, ok := srv.Object("bucket", "src.png"); !ok {
+		t.Fatal("source deleted after failed verification")
+	}
+}
+
+func TestMovePrefixPlanResume(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	meta := map[string]string{"Content-Type": "image/jpeg", "X-Oss-Meta-Owner": "alice"}
+	for _, key := range []string{"photos/a.jpg", "photos/b.jpg", "photos/c.jpg"} {
+		srv.PutObject("bucket", key, []byte(key), meta)
+	}
+	//模拟中断：a.jpg已复制但源未删除，b.jpg的目标内容相同但元数据不同
+	if _, err := client.Copy("bucket", "archive/a.jpg", "/bucket/photos/a.jpg", map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	srv.PutObject("bucket", "archive/b.jpg", []byte("photos/b.jpg"), map[string]string{"Content-Type": "image/jpeg", "X-Oss-Meta-Owner": "bob"})
+
+	plan, err := client.MovePrefixPlan("bucket", "archive/", "/bucket/photos/", map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := planActions(plan)
+	if len(actions) != 3 || actions["photos/a.jpg"] != PlanDelete || actions["archive/b.jpg"] != PlanMove || actions["archive/c.jpg"] != PlanMove {
+		t.Fatalf("unexpected plan %v", actions)
+	}
+	for _, v := range plan.Actions {
+		if v.Key == "photos/a.jpg" && v.Reason != "already copied" {
+			t.Fatalf("reason = %q, want already copied", v.Reason)
+		}
+	}
+
+	res, err := client.ExecutePlan(plan, map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res["finish"] != 3 {
+		t.Fatalf("finish = %d, want 3", res["finish"])
+	}
+	if keys := srv.Keys("bucket"); len(keys) != 3 || keys[0] != "archive/a.jpg" || keys[2] != "archive/c.jpg" {
+		t.Fatalf("keys after move: %v", keys)
+	}
+	if object, _ := srv.Object("bucket", "archive/b.jpg"); object.Headers["X-Oss-Meta-Owner"] != "alice" {
+		t.Fatal("archive/b.jpg not replaced")
+	}
+}
@@ -64,7 +64,11 @@ func (this *Client) UploadLargeFile(filePath, bucket, object string, options map
 		this.threadMaxNum = total
 	}
 	//初化化上传
-	initUpload, err := this.initUpload(bucket, object, map[string]string{"disposition": options["disposition"]})
+	initUpload, err := this.initUpload(bucket, object, this.metaOptions(map[string]string{
+		"disposition": options["disposition"],
+		"sse":         options["sse"],
+		"sse-key-id":  options["sse-key-id"],
+	}, options))
 	if err != nil {
 		return nil, err
 	}
@@ -124,12 +128,7 @@ func (this *Client) UploadLargeFile(filePath, bucket, object string, options map
 	close(uploadPercent)
 	<-uploadDone
 	//上传完成
-	completeUploadInfo := "<CompleteMultipartUpload>"
-	for partNum, part := range uploadPartList {
-		completeUploadInfo += fmt.Sprintf("<Part><PartNumber>%d</PartNumber><ETag>%s</ETag></Part>", partNum+1, part["Etag"])
-	}
-	completeUploadInfo += "</CompleteMultipartUpload>"
-	return this.completeUpload([]byte(completeUploadInfo), bucket, object, initUpload["UploadId"])
+	return this.CompleteUpload(bucket, object, initUpload["UploadId"], uploadPartList)
 }
 
 func (this *Client) CopyLargeFile(bucket, object, source string, options map[string]string) (map[string]string, error) {
@@ -138,7 +137,7 @@ func (this *Client) CopyLargeFile(bucket, object, source string, options map[str
 	tmpSourceInfo := strings.Split(source, "/")
 	sourceBucket := tmpSourceInfo[1]
 	sourceObject := strings.Join(tmpSourceInfo[2:], "/")
-	sourceHead, err := this.Head(sourceBucket, sourceObject)
+	sourceHead, err := this.Head(sourceBucket, sourceObject, map[string]string{"versionId": options["versionId"]})
 	if err != nil {
 		return nil, err
 	}
@@ -176,10 +175,18 @@ func (this *Client) CopyLargeFile(bucket, object, source string, options map[str
 	}
 
 	//初化化上传
-	initUpload, err := this.initUpload(bucket, object, map[string]string{"disposition": options["disposition"]})
+	initUpload, err := this.initUpload(bucket, object, this.metaOptions(this.sourceMetaOptions(map[string]string{
+		"disposition": options["disposition"],
+		"sse":         options["sse"],
+		"sse-key-id":  options["sse-key-id"],
+	}, sourceHead, options), options))
 	if err != nil {
 		return nil, err
 	}
+	//复制源object的指定版本
+	if options["versionId"] != "" {
+		source += "?versionId=" + options["versionId"]
+	}
 	var copyPartList = make([]map[string]string, total)
 	var queueMaxSize = make(chan bool, this.threadMaxNum)
 	var copyPercent = make(chan bool)
@@ -239,25 +246,51 @@ func (this *Client) CopyLargeFile(bucket, object, source string, options map[str
 	close(copyPercent)
 	<-copyDone
 	//copy完成
-	completeCopyInfo := "<CompleteMultipartUpload>"
-	for partNum, part := range copyPartList {
-		completeCopyInfo += fmt.Sprintf("<Part><PartNumber>%d</PartNumber><ETag>%s</ETag></Part>", partNum+1, part["Etag"])
+	return this.CompleteUpload(bucket, object, initUpload["UploadId"], copyPartList)
+}
+
+func (this *Client) InitUpload(bucket, object string, options map[string]string) (map[string]string, error) {
+	return this.initUpload(bucket, object, options)
+}
+
+func (this *Client) UploadPart(body *io.SectionReader, bucket, object string, partNumber int, uploadId string) (map[string]string, error) {
+	return this.uploadPart(body, bucket, object, partNumber, uploadId)
+}
+
+// partList按分片顺序排列，取每个分片的Etag
+func (this *Client) CompleteUpload(bucket, object, uploadId string, partList []map[string]string) (map[string]string, error) {
+	completeUploadInfo := "<CompleteMultipartUpload>"
+	for partNum, part := range partList {
+		completeUploadInfo += fmt.Sprintf("<Part><PartNumber>%d</PartNumber><ETag>%s</ETag></Part>", partNum+1, part["Etag"])
 	}
-	completeCopyInfo += "</CompleteMultipartUpload>"
-	return this.completeUpload([]byte(completeCopyInfo), bucket, object, initUpload["UploadId"])
+	completeUploadInfo += "</CompleteMultipartUpload>"
+	return this.completeUpload([]byte(completeUploadInfo), bucket, object, uploadId)
 }
 
 func (this *Client) initUpload(bucket, object string, options map[string]string) (map[string]string, error) {
 	addr := fmt.Sprintf("http://%s%s/%s?uploads", bucket, this.host, object)
 	method := "POST"
 	contentType := mime.TypeByExtension(path.Ext(object))
+	if options["content-type"] != "" {
+		contentType = options["content-type"]
+	}
 	date := time.Unix(time.Now().Unix()-8*3600, 0).Format(this.dateTimeGMT)
 	headers := map[string]string{
 		"Content-Type": contentType,
 		"Date":         date,
 	}
+	if err := this.setEncryptionHeaders(headers, options); err != nil {
+		return nil, err
+	}
+	this.setMetaHeaders(headers, options)
 	LF := "\n"
 	headers["Authorization"] = this.sign(method+LF, headers, bucket, fmt.Sprintf("%s?uploads", object))
+	//不参与签名的标准头
+	for _, k := range copyStandardHeaders[1:] {
+		if options[strings.ToLower(k)] != "" {
+			headers[k] = options[strings.ToLower(k)]
+		}
+	}
 	if options["disposition"] != "" {
 		headers["Content-Disposition"] = fmt.Sprintf(`attachment; filename="%s"`, options["disposition"])
 	}
@@ -343,9 +376,11 @@ func (this *Client) completeUpload(body []byte, bucket, object, uploadId string)
 		return nil, err
 	}
 	return map[string]string{
-		"Location": completeUpload.Location,
-		"Bucket":   completeUpload.Bucket,
-		"Key":      completeUpload.Key,
-		"ETag":     completeUpload.ETag,
+		"Location":                  completeUpload.Location,
+		"Bucket":                    completeUpload.Bucket,
+		"Key":                       completeUpload.Key,
+		"ETag":                      completeUpload.ETag,
+		"ServerSideEncryption":      res["X-Oss-Server-Side-Encryption"],
+		"ServerSideEncryptionKeyId": res["X-Oss-Server-Side-Encryption-Key-Id"],
 	}, nil
 }
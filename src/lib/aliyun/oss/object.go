@@ -6,33 +6,52 @@ import (
 	"fmt"
 	"io/ioutil"
 	"mime"
+	"net/url"
 	"os"
 	"path"
 	"runtime"
 	"strconv"
 	"strings"
 	"sync"
-	"sync/atomic"
 	"time"
 )
 
 type ListObjectResult struct {
-	Name        string               `xml:"Name"`
-	Prefix      string               `xml:"Prefix"`
-	Marker      string               `xml:"Marker"`
-	MaxKeys     string               `xml:"MaxKeys"`
-	Delimiter   string               `xml:"Delimiter"`
-	IsTruncated string               `xml:"IsTruncated"`
-	Contents    []ListObjectContents `xml:"Contents"`
+	Name           string               `xml:"Name"`
+	Prefix         string               `xml:"Prefix"`
+	Marker         string               `xml:"Marker"`
+	NextMarker     string               `xml:"NextMarker"`
+	MaxKeys        string               `xml:"MaxKeys"`
+	Delimiter      string               `xml:"Delimiter"`
+	EncodingType   string               `xml:"EncodingType"`
+	IsTruncated    string               `xml:"IsTruncated"`
+	Contents       []ListObjectContents `xml:"Contents"`
+	CommonPrefixes []ListCommonPrefix   `xml:"CommonPrefixes"`
 }
 
 type ListObjectContents struct {
-	Key          string `xml:"Key"`
-	LastModified string `xml:"LastModified"`
-	ETag         string `xml:"ETag"`
-	Type         string `xml:"Type"`
-	Size         string `xml:"Size"`
-	StorageClass string `xml:"StorageClass"`
+	Key          string      `xml:"Key"`
+	LastModified string      `xml:"LastModified"`
+	ETag         string      `xml:"ETag"`
+	Type         string      `xml:"Type"`
+	Size         string      `xml:"Size"`
+	StorageClass string      `xml:"StorageClass"`
+	Owner        ObjectOwner `xml:"Owner"`
+}
+
+type ListObjectsV2Result struct {
+	Name                  string               `xml:"Name"`
+	Prefix                string               `xml:"Prefix"`
+	StartAfter            string               `xml:"StartAfter"`
+	ContinuationToken     string               `xml:"ContinuationToken"`
+	NextContinuationToken string               `xml:"NextContinuationToken"`
+	MaxKeys               string               `xml:"MaxKeys"`
+	KeyCount              string               `xml:"KeyCount"`
+	Delimiter             string               `xml:"Delimiter"`
+	EncodingType          string               `xml:"EncodingType"`
+	IsTruncated           string               `xml:"IsTruncated"`
+	Contents              []ListObjectContents `xml:"Contents"`
+	CommonPrefixes        []ListCommonPrefix   `xml:"CommonPrefixes"`
 }
 
 func (this *Client) UploadFile(filePath, bucket, object string, options map[string]string) (map[string]string, error) {
@@ -57,7 +76,11 @@ func (this *Client) UploadFile(filePath, bucket, object string, options map[stri
 	if strings.TrimRight(object, "/") == path.Dir(object) {
 		object = strings.TrimRight(object, "/") + "/" + path.Base(filePath)
 	}
-	return this.Put(body, bucket, object, map[string]string{"disposition": options["disposition"]})
+	return this.Put(body, bucket, object, map[string]string{
+		"disposition": options["disposition"],
+		"sse":         options["sse"],
+		"sse-key-id":  options["sse-key-id"],
+	})
 }
 
 func (this *Client) Put(body []byte, bucket, object string, options map[string]string) (map[string]string, error) {
@@ -72,6 +95,10 @@ func (this *Client) Put(body []byte, bucket, object string, options map[string]s
 		"Content-Type": contentType,
 		"Date":         date,
 	}
+	if err := this.setEncryptionHeaders(headers, options); err != nil {
+		return nil, err
+	}
+	this.setMetaHeaders(headers, options)
 	headers["Authorization"] = this.sign(method, headers, bucket, object)
 	headers["Content-Length"] = contentLength
 	if options["disposition"] != "" {
@@ -85,15 +112,19 @@ func (this *Client) Put(body []byte, bucket, object string, options map[string]s
 		return nil, err
 	}
 	return map[string]string{
-		"X-Oss-Request-Id": res["X-Oss-Request-Id"],
-		"StatusCode":       res["StatusCode"],
-		"Location":         "http://" + bucket + this.host + "/" + object,
-		"Bucket":           bucket,
-		"ETag":             res["Etag"],
-		"Key":              object,
+		"X-Oss-Request-Id":          res["X-Oss-Request-Id"],
+		"StatusCode":                res["StatusCode"],
+		"Location":                  "http://" + bucket + this.host + "/" + object,
+		"Bucket":                    bucket,
+		"ETag":                      res["Etag"],
+		"Key":                       object,
+		"VersionId":                 res["X-Oss-Version-Id"],
+		"ServerSideEncryption":      res["X-Oss-Server-Side-Encryption"],
+		"ServerSideEncryptionKeyId": res["X-Oss-Server-Side-Encryption-Key-Id"],
 	}, nil
 }
 
+// options：versionId、sse、x-oss-meta-*(替换源object的元数据)、x-oss-tagging，content-type在替换元数据时使用
 func (this *Client) Copy(bucket, object, source string, options map[string]string) (map[string]string, error) {
 	addr := fmt.Sprintf("http://%s%s/%s", bucket, this.host, object)
 	method := "PUT"
@@ -102,8 +133,33 @@ func (this *Client) Copy(bucket, object, source string, options map[string]strin
 		"Date":              date,
 		"x-oss-copy-source": source,
 	}
+	//复制源object的指定版本
+	if options["versionId"] != "" {
+		headers["x-oss-copy-source"] = source + "?versionId=" + options["versionId"]
+	}
+	if err := this.setEncryptionHeaders(headers, options); err != nil {
+		return nil, err
+	}
+	//指定了元数据或标签时替换源object的元数据或标签
+	this.setMetaHeaders(headers, options)
+	if headers["x-oss-tagging"] != "" {
+		headers["x-oss-tagging-directive"] = "Replace"
+	}
 	LF := "\n"
-	headers["Authorization"] = this.sign(method+LF+LF, headers, bucket, object)
+	if !hasMetaOptions(options) {
+		headers["Authorization"] = this.sign(method+LF+LF, headers, bucket, object)
+	} else {
+		//替换元数据时Content-Type等标准头也会被替换，沿用源object的标准头
+		headers["x-oss-metadata-directive"] = "REPLACE"
+		sourceMeta := this.copySourceMeta(source, options)
+		headers["Content-Type"] = sourceMeta["content-type"]
+		headers["Authorization"] = this.sign(method+LF, headers, bucket, object)
+		for _, k := range copyStandardHeaders[1:] {
+			if sourceMeta[strings.ToLower(k)] != "" {
+				headers[k] = sourceMeta[strings.ToLower(k)]
+			}
+		}
+	}
 	if options["disposition"] != "" {
 		headers["response-content-disposition"] = fmt.Sprintf(`attachment; filename="%s"`, options["disposition"])
 	}
@@ -114,15 +170,35 @@ func (this *Client) Copy(bucket, object, source string, options map[string]strin
 	return res, nil
 }
 
-func (this *Client) Delete(bucket, object string) (map[string]string, error) {
-	addr := fmt.Sprintf("http://%s%s/%s", bucket, this.host, object)
+// 复制源object的标准头(小写key)，content-type依次取options、源object的Content-Type、源object扩展名
+func (this *Client) copySourceMeta(source string, options map[string]string) map[string]string {
+	sourceMeta := map[string]string{}
+	tmpSourceInfo := strings.Split(source, "/")
+	if len(tmpSourceInfo) >= 3 {
+		sourceHead, err := this.Head(tmpSourceInfo[1], strings.Join(tmpSourceInfo[2:], "/"), map[string]string{"versionId": options["versionId"]})
+		if err == nil && sourceHead["StatusCode"] == "200" {
+			sourceMeta = this.sourceMetaOptions(sourceMeta, sourceHead, options)
+		}
+	}
+	if options["content-type"] != "" {
+		sourceMeta["content-type"] = options["content-type"]
+	}
+	if sourceMeta["content-type"] == "" {
+		sourceMeta["content-type"] = mime.TypeByExtension(path.Ext(source))
+	}
+	return sourceMeta
+}
+
+func (this *Client) Delete(bucket, object string, options ...map[string]string) (map[string]string, error) {
+	query, subResource := this.versionIdParam(this.firstOptions(options)["versionId"])
+	addr := fmt.Sprintf("http://%s%s/%s%s", bucket, this.host, object, query)
 	method := "DELETE"
 	date := time.Unix(time.Now().Unix()-8*3600, 0).Format(this.dateTimeGMT)
 	headers := map[string]string{
 		"Date": date,
 	}
 	LF := "\n"
-	headers["Authorization"] = this.sign(method+LF+LF, headers, bucket, object)
+	headers["Authorization"] = this.sign(method+LF+LF, headers, bucket, object+subResource)
 	res, err := this.curl(addr, method, headers, []byte(""))
 	if err != nil {
 		return nil, err
@@ -130,26 +206,133 @@ func (this *Client) Delete(bucket, object string) (map[string]string, error) {
 	return res, nil
 }
 
-func (this *Client) Head(bucket, object string) (map[string]string, error) {
-	addr := fmt.Sprintf("http://%s%s/%s", bucket, this.host, object)
+// 解冻归档或冷归档object，options：days为解冻天数，tier为冷归档解冻优先级(Expedited、Standard、Bulk)
+func (this *Client) RestoreObject(bucket, object string, options map[string]string) (map[string]string, error) {
+	subResource := "?restore"
+	addr := fmt.Sprintf("http://%s%s/%s%s", bucket, this.host, object, subResource)
+	method := "POST"
+	date := time.Unix(time.Now().Unix()-8*3600, 0).Format(this.dateTimeGMT)
+	headers := map[string]string{
+		"Date": date,
+	}
+	body := ""
+	if options["days"] != "" || options["tier"] != "" {
+		body = "<RestoreRequest>"
+		if options["days"] != "" {
+			body += "<Days>" + options["days"] + "</Days>"
+		}
+		if options["tier"] != "" {
+			body += "<JobParameters><Tier>" + options["tier"] + "</Tier></JobParameters>"
+		}
+		body += "</RestoreRequest>"
+	}
+	LF := "\n"
+	if body != "" {
+		headers["Content-Md5"] = this.base64(this.md5Byte([]byte(body)))
+		headers["Content-Type"] = "application/xml"
+		headers["Authorization"] = this.sign(method, headers, bucket, object+subResource)
+		headers["Content-Length"] = strconv.Itoa(len(body))
+	} else {
+		headers["Authorization"] = this.sign(method+LF+LF, headers, bucket, object+subResource)
+	}
+	res, err := this.curl(addr, method, headers, []byte(body))
+	if err != nil {
+		return nil, err
+	}
+	//200为已解冻，202为开始解冻
+	if res["StatusCode"] != "200" && res["StatusCode"] != "202" {
+		return nil, this.parseError(res)
+	}
+	return res, nil
+}
+
+func (this *Client) PutSymlink(bucket, symlink, target string, options map[string]string) (map[string]string, error) {
+	addr := fmt.Sprintf("http://%s%s/%s?symlink", bucket, this.host, symlink)
+	method := "PUT"
+	date := time.Unix(time.Now().Unix()-8*3600, 0).Format(this.dateTimeGMT)
+	headers := map[string]string{
+		"Date":                 date,
+		"x-oss-symlink-target": url.QueryEscape(target),
+	}
+	LF := "\n"
+	headers["Authorization"] = this.sign(method+LF+LF, headers, bucket, symlink+"?symlink")
+	if options["disposition"] != "" {
+		headers["Content-Disposition"] = fmt.Sprintf(`attachment; filename="%s"`, options["disposition"])
+	}
+	res, err := this.curl(addr, method, headers, []byte(""))
+	if err != nil {
+		return nil, err
+	}
+	if res["StatusCode"] != "200" {
+		return nil, this.parseError(res)
+	}
+	return map[string]string{
+		"X-Oss-Request-Id": res["X-Oss-Request-Id"],
+		"StatusCode":       res["StatusCode"],
+		"Bucket":           bucket,
+		"ETag":             res["Etag"],
+		"Key":              symlink,
+		"Target":           target,
+	}, nil
+}
+
+func (this *Client) GetSymlink(bucket, symlink string) (map[string]string, error) {
+	addr := fmt.Sprintf("http://%s%s/%s?symlink", bucket, this.host, symlink)
+	method := "GET"
+	date := time.Unix(time.Now().Unix()-8*3600, 0).Format(this.dateTimeGMT)
+	headers := map[string]string{
+		"Date": date,
+	}
+	LF := "\n"
+	headers["Authorization"] = this.sign(method+LF+LF, headers, bucket, symlink+"?symlink")
+	res, err := this.curl(addr, method, headers, []byte(""))
+	if err != nil {
+		return nil, err
+	}
+	if res["StatusCode"] != "200" {
+		return nil, this.parseError(res)
+	}
+	target, err := url.QueryUnescape(res["X-Oss-Symlink-Target"])
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"X-Oss-Request-Id": res["X-Oss-Request-Id"],
+		"StatusCode":       res["StatusCode"],
+		"Bucket":           bucket,
+		"ETag":             res["Etag"],
+		"Key":              symlink,
+		"Target":           target,
+	}, nil
+}
+
+func (this *Client) Head(bucket, object string, options ...map[string]string) (map[string]string, error) {
+	query, subResource := this.versionIdParam(this.firstOptions(options)["versionId"])
+	addr := fmt.Sprintf("http://%s%s/%s%s", bucket, this.host, object, query)
 	method := "HEAD"
 	date := time.Unix(time.Now().Unix()-8*3600, 0).Format(this.dateTimeGMT)
 	headers := map[string]string{
 		"Date": date,
 	}
 	LF := "\n"
-	headers["Authorization"] = this.sign(method+LF+LF, headers, bucket, object)
+	headers["Authorization"] = this.sign(method+LF+LF, headers, bucket, object+subResource)
 	res, err := this.curl(addr, method, headers, []byte(""))
 	if err != nil {
 		return nil, err
 	}
+	if res["X-Oss-Server-Side-Encryption"] != "" {
+		res["ServerSideEncryption"] = res["X-Oss-Server-Side-Encryption"]
+		res["ServerSideEncryptionKeyId"] = res["X-Oss-Server-Side-Encryption-Key-Id"]
+		res["ServerSideDataEncryption"] = res["X-Oss-Server-Side-Data-Encryption"]
+	}
 	return res, nil
 }
 
-func (this *Client) Get(bucket, object, localfile string) (map[string]string, error) {
+func (this *Client) Get(bucket, object, localfile string, options ...map[string]string) (map[string]string, error) {
 	var wg sync.WaitGroup
 	runtime.GOMAXPROCS(runtime.NumCPU())
-	objectHead, err := this.Head(bucket, object)
+	versionId := this.firstOptions(options)["versionId"]
+	objectHead, err := this.Head(bucket, object, map[string]string{"versionId": versionId})
 	if err != nil {
 		return nil, err
 	}
@@ -210,7 +393,7 @@ func (this *Client) Get(bucket, object, localfile string) (map[string]string, er
 			partRange := fmt.Sprintf("bytes=%d-%d", tmpStart, tmpEnd)
 			isWriteSuccess := false
 			for i := 0; i < this.maxRetryNum; i++ {
-				tmp, err := this.Cat(bucket, object, partRange)
+				tmp, err := this.Cat(bucket, object, partRange, versionId)
 				if err != nil {
 					continue
 				}
@@ -239,22 +422,35 @@ func (this *Client) Get(bucket, object, localfile string) (map[string]string, er
 	return map[string]string{"object": object, "localfile": localfile}, nil
 }
 
+// param依次为Range和versionId，均可省略
 func (this *Client) Cat(bucket, object string, param ...string) (map[string]string, error) {
-	addr := fmt.Sprintf("http://%s%s/%s", bucket, this.host, object)
-	method := "GET"
-	date := time.Unix(time.Now().Unix()-8*3600, 0).Format(this.dateTimeGMT)
-	headers := map[string]string{
-		"Date": date,
+	versionId := ""
+	if len(param) > 1 {
+		versionId = param[1]
 	}
-	LF := "\n"
-	headers["Authorization"] = this.sign(method+LF+LF, headers, bucket, object)
 	//分片请求
 	partRange := ""
 	if len(param) > 0 {
 		partRange = param[0]
 	}
-	if partRange != "" {
-		headers["Range"] = partRange
+	return this.cat(bucket, object, versionId, map[string]string{"Range": partRange})
+}
+
+// conditions为不参与签名的请求头，如Range、If-Match，值为空时不发送
+func (this *Client) cat(bucket, object, versionId string, conditions map[string]string) (map[string]string, error) {
+	query, subResource := this.versionIdParam(versionId)
+	addr := fmt.Sprintf("http://%s%s/%s%s", bucket, this.host, object, query)
+	method := "GET"
+	date := time.Unix(time.Now().Unix()-8*3600, 0).Format(this.dateTimeGMT)
+	headers := map[string]string{
+		"Date": date,
+	}
+	LF := "\n"
+	headers["Authorization"] = this.sign(method+LF+LF, headers, bucket, object+subResource)
+	for k, v := range conditions {
+		if v != "" {
+			headers[k] = v
+		}
 	}
 	res, err := this.curl(addr, method, headers, []byte(""))
 	if err != nil {
@@ -264,138 +460,27 @@ func (this *Client) Cat(bucket, object string, param ...string) (map[string]stri
 }
 
 func (this *Client) UploadFromDir(localdir, bucket, prefix string, options map[string]string) (map[string]int, error) {
-	var wg sync.WaitGroup
-	runtime.GOMAXPROCS(runtime.NumCPU())
-	suffix := ""
-	if options["suffix"] != "" {
-		suffix = options["suffix"]
-	}
-	localdir = strings.TrimRight(localdir, "/") + "/"
-	fileList := this.walkdir(localdir, suffix)
-	total := len(fileList)
-	tmpSkip := int64(0)
-	tmpFinish := int64(0)
-	if options["thread_num"] != "" {
-		threadNum, err := strconv.Atoi(options["thread_num"])
-		if err == nil && threadNum <= this.threadMaxNum && threadNum >= this.threadMinNum {
-			this.threadMaxNum = threadNum
-		}
-	}
-	if total < this.threadMaxNum {
-		this.threadMaxNum = total
-	}
-
-	var queueMaxSize = make(chan bool, this.threadMaxNum)
-	var filePercent = make(chan bool)
-	var fileDone = make(chan struct{})
-
-	//实时进度
-	go func() {
-		finishNum := 0
-		for {
-			_, ok := <-filePercent
-			if !ok {
-				close(fileDone)
-				break
-			}
-			finishNum++
-			fmt.Printf("\r%.0f%%", float64(finishNum)/float64(total)*100)
-		}
-	}()
-
-	fileNum := 0
-	for {
-		if fileNum >= total {
-			break
-		}
-		wg.Add(1)
-		queueMaxSize <- true
-		go func(fileName string) {
-			defer wg.Done()
-			object := strings.TrimRight(prefix, "/") + "/"
-			//if dir := path.Dir(fileName); dir != "." {
-			//	object += dir + "/"
-			//}
-			//object += this.md5([]byte(path.Base(fileName))) + path.Ext(fileName)
-			object += fileName
-			object = strings.TrimLeft(object, "/")
-			isSkipped := false
-			if options["replace"] != "true" {
-				localFileStat, err := os.Stat(localdir + fileName)
-				if err != nil {
-					fmt.Printf("UploadFromDir::Stat Fail,FileName: %s\n", fileName)
-					os.Exit(2)
-				}
-				localFileSize := localFileStat.Size()
-				localFileModifyTime := time.Unix(localFileStat.ModTime().Unix()-8*3600, 0).Format(this.dateTimeGMT)
-				objectHead, _ := this.Head(bucket, object)
-				objectHeadSize, _ := strconv.ParseInt(objectHead["Content-Length"], 10, 64)
-				if objectHead["StatusCode"] == "200" && localFileSize == objectHeadSize {
-					objectTime, _ := time.Parse(this.dateTimeGMT, objectHead["Last-Modified"])
-					localTime, _ := time.Parse(this.dateTimeGMT, localFileModifyTime)
-					if objectTime.Unix() >= localTime.Unix() {
-						isSkipped = true
-						atomic.AddInt64(&tmpSkip, 1)
-					}
-				}
-			}
-			if !isSkipped {
-				fd, err := os.Open(localdir + fileName)
-				if err != nil {
-					fmt.Printf("UploadFromDir::Open Fail,FileName: %s\n", fileName)
-					os.Exit(2)
-				}
-				defer fd.Close()
-				body, err := ioutil.ReadAll(fd)
-				if err != nil {
-					fmt.Printf("UploadFromDir::ReadAll Fail,FileName: %s\n", fileName)
-					os.Exit(2)
-				}
-				isUploadSuccess := false
-				for i := 0; i < this.maxRetryNum; i++ {
-					res, err := this.Put(body, bucket, object, map[string]string{"disposition": fileName})
-					if err != nil {
-						continue
-					}
-					isUploadSuccess = true
-					if res["StatusCode"] == "200" {
-						atomic.AddInt64(&tmpFinish, 1)
-					}
-					break
-				}
-				if !isUploadSuccess {
-					fmt.Printf("\nUploadFromDir::Fail,FileName: %s\n", fileName)
-					os.Exit(2)
-				}
-			}
-			filePercent <- true
-			<-queueMaxSize
-		}(fileList[fileNum])
-		fileNum++
+	plan, err := this.UploadFromDirPlan(localdir, bucket, prefix, options)
+	if err != nil {
+		return nil, err
 	}
-	wg.Wait()
-	close(filePercent)
-	<-fileDone
-	skip := int(atomic.LoadInt64(&tmpSkip))
-	finish := int(atomic.LoadInt64(&tmpFinish))
-	return map[string]int{"total": total, "skip": skip, "finish": finish}, nil
+	return this.ExecutePlan(plan, options)
 }
 
+// 默认使用encoding-type=url请求，返回前解码Key、Prefix等字段
 func (this *Client) ListObject(bucket string, options map[string]string) (*ListObjectResult, error) {
-	param := ""
-	if options["delimiter"] != "" {
-		param += "&delimiter=" + options["delimiter"]
-	}
-	if options["marker"] != "" {
-		param += "&marker=" + options["marker"]
-	}
-	if options["max-keys"] != "" {
-		param += "&max-keys=" + options["max-keys"]
-	}
-	if options["prefix"] != "" {
-		param += "&prefix=" + options["prefix"]
+	encodingType := options["encoding-type"]
+	if encodingType == "" {
+		encodingType = "url"
+	}
+	param := url.Values{}
+	for _, k := range []string{"delimiter", "marker", "max-keys", "prefix"} {
+		if options[k] != "" {
+			param.Set(k, options[k])
+		}
 	}
-	addr := "http://" + bucket + this.host + "/?" + strings.TrimLeft(param, "&")
+	param.Set("encoding-type", encodingType)
+	addr := "http://" + bucket + this.host + "/?" + param.Encode()
 	method := "GET"
 	date := time.Unix(time.Now().Unix()-8*3600, 0).Format(this.dateTimeGMT)
 	headers := map[string]string{
@@ -407,219 +492,96 @@ func (this *Client) ListObject(bucket string, options map[string]string) (*ListO
 	if err != nil {
 		return nil, err
 	}
+	if res["StatusCode"] != "200" {
+		return nil, this.parseError(res)
+	}
 	var listObject ListObjectResult
 	if err := xml.Unmarshal([]byte(res["Body"]), &listObject); err != nil {
 		return nil, err
 	}
+	if listObject.EncodingType == "url" {
+		fields := []*string{&listObject.Prefix, &listObject.Marker, &listObject.NextMarker, &listObject.Delimiter}
+		if err := this.decodeListResult(fields, listObject.Contents, listObject.CommonPrefixes); err != nil {
+			return nil, err
+		}
+	}
 	return &listObject, nil
 }
 
-func (this *Client) CopyAllObject(bucket, prefix, source string, options map[string]string) (map[string]int, error) {
-	var wg sync.WaitGroup
-	runtime.GOMAXPROCS(runtime.NumCPU())
-	marker := ""
-	tmpSourceInfo := strings.Split(source, "/")
-	sourceBucket := tmpSourceInfo[1]
-	sourcePrefix := strings.Join(tmpSourceInfo[2:], "/")
-	total := 0
-	tmpSkip := int64(0)
-	tmpFinish := int64(0)
-	if options["thread_num"] != "" {
-		threadNum, err := strconv.Atoi(options["thread_num"])
-		if err == nil && threadNum <= this.threadMaxNum && threadNum >= this.threadMinNum {
-			this.threadMaxNum = threadNum
+// options: prefix、delimiter、max-keys、start-after、continuation-token、fetch-owner(true/false)、encoding-type
+func (this *Client) ListObjectsV2(bucket string, options map[string]string) (*ListObjectsV2Result, error) {
+	encodingType := options["encoding-type"]
+	if encodingType == "" {
+		encodingType = "url"
+	}
+	param := url.Values{}
+	for _, k := range []string{"delimiter", "max-keys", "prefix", "start-after", "continuation-token", "fetch-owner"} {
+		if options[k] != "" {
+			param.Set(k, options[k])
 		}
 	}
-	var queueMaxSize = make(chan bool, this.threadMaxNum)
-	var copyPercent = make(chan bool)
-	var copyDone = make(chan struct{})
-
-	//实时进度
-	go func() {
-		finishNum := 0
-		for {
-			_, ok := <-copyPercent
-			if !ok {
-				close(copyDone)
-				break
-			}
-			finishNum++
-			fmt.Printf("\r%.0f%%", float64(finishNum)/float64(total)*100)
-		}
-	}()
-LIST:
-	sourceList, err := this.ListObject(sourceBucket, map[string]string{"prefix": sourcePrefix, "marker": marker, "max-keys": "1000"})
-	if err != nil {
-		return nil, err
+	param.Set("list-type", "2")
+	param.Set("encoding-type", encodingType)
+	addr := "http://" + bucket + this.host + "/?" + param.Encode()
+	method := "GET"
+	date := time.Unix(time.Now().Unix()-8*3600, 0).Format(this.dateTimeGMT)
+	headers := map[string]string{
+		"Date": date,
 	}
-	sourceObjectNum := len(sourceList.Contents)
-	total += sourceObjectNum
-	fileNum := 0
-	for {
-		if fileNum >= sourceObjectNum {
-			break
-		}
-		wg.Add(1)
-		queueMaxSize <- true
-		go func(objectInfo ListObjectContents) {
-			defer wg.Done()
-			object := strings.TrimRight(prefix, "/") + "/" + path.Base(objectInfo.Key)
-			sourceObject := "/" + sourceBucket + "/" + objectInfo.Key
-			isSkipped := false
-			if options["replace"] != "true" {
-				objectHead, _ := this.Head(bucket, object)
-				if objectHead["StatusCode"] == "200" {
-					objectHeadSize, _ := strconv.ParseInt(objectHead["Content-Length"], 10, 64)
-					sourceHead, _ := this.Head(sourceBucket, objectInfo.Key)
-					sourceHeadSize, _ := strconv.ParseInt(sourceHead["Content-Length"], 10, 64)
-					if sourceHead["StatusCode"] == "200" && objectHeadSize == sourceHeadSize {
-						objectTime, _ := time.Parse(this.dateTimeGMT, objectHead["Last-Modified"])
-						sourceTime, _ := time.Parse(this.dateTimeGMT, sourceHead["Last-Modified"])
-						if objectTime.Unix() >= sourceTime.Unix() {
-							isSkipped = true
-							atomic.AddInt64(&tmpSkip, 1)
-						}
-					}
-				}
-			}
-			if !isSkipped {
-				isCopySuccess := false
-				for i := 0; i < this.maxRetryNum; i++ {
-					res, err := this.Copy(bucket, object, sourceObject, map[string]string{"disposition": options["disposition"]})
-					if err != nil {
-						continue
-					}
-					isCopySuccess = true
-					if res["StatusCode"] == "200" {
-						atomic.AddInt64(&tmpFinish, 1)
-					}
-					break
-				}
-				if !isCopySuccess {
-					fmt.Printf("\nCopy File Fail,object:%s\n", object)
-					os.Exit(2)
-				}
-			}
-			copyPercent <- true
-			<-queueMaxSize
-		}(sourceList.Contents[fileNum])
-		fileNum++
+	//continuation-token属于签名子资源
+	subResource := ""
+	if options["continuation-token"] != "" {
+		subResource = "?continuation-token=" + options["continuation-token"]
 	}
-	wg.Wait()
-	if sourceList.IsTruncated == "true" {
-		marker = sourceList.Contents[sourceObjectNum-1].Key
-		goto LIST
-	}
-	close(copyPercent)
-	<-copyDone
-	skip := int(atomic.LoadInt64(&tmpSkip))
-	finish := int(atomic.LoadInt64(&tmpFinish))
-	return map[string]int{"total": total, "skip": skip, "finish": finish}, nil
-}
-
-func (this *Client) DeleteAllObject(bucket, prefix string, options map[string]string) (map[string]int, error) {
-	var wg sync.WaitGroup
-	runtime.GOMAXPROCS(runtime.NumCPU())
-	bodyList := make([]string, 0)
-	bodyListNum := make([]int, 0)
-	marker := ""
-	total := 0
-	tmpFinish := int64(0)
-LIST:
-	list, err := this.ListObject(bucket, map[string]string{"prefix": prefix, "marker": marker, "max-keys": "1000"})
+	LF := "\n"
+	headers["Authorization"] = this.sign(method+LF+LF, headers, bucket, subResource)
+	res, err := this.curl(addr, method, headers, []byte(""))
 	if err != nil {
 		return nil, err
 	}
-	total += len(list.Contents)
-	if total <= 0 {
-		return map[string]int{"total": 0, "finish": 0}, nil
+	if res["StatusCode"] != "200" {
+		return nil, this.parseError(res)
 	}
-	body := "<Delete>"
-	body += "<Quiet>true</Quiet>"
-	for _, v := range list.Contents {
-		body += "<Object><Key>" + v.Key + "</Key></Object>"
-		marker = v.Key
+	var listObject ListObjectsV2Result
+	if err := xml.Unmarshal([]byte(res["Body"]), &listObject); err != nil {
+		return nil, err
 	}
-	body += "</Delete>"
-	bodyList = append(bodyList, body)
-	bodyListNum = append(bodyListNum, len(list.Contents))
-	if list.IsTruncated == "true" {
-		goto LIST
+	if listObject.EncodingType == "url" {
+		fields := []*string{&listObject.Prefix, &listObject.StartAfter, &listObject.Delimiter}
+		if err := this.decodeListResult(fields, listObject.Contents, listObject.CommonPrefixes); err != nil {
+			return nil, err
+		}
 	}
+	return &listObject, nil
+}
 
-	if options["thread_num"] != "" {
-		threadNum, err := strconv.Atoi(options["thread_num"])
-		if err == nil && threadNum <= this.threadMaxNum && threadNum >= this.threadMinNum {
-			this.threadMaxNum = threadNum
+func (this *Client) decodeListResult(fields []*string, contents []ListObjectContents, commonPrefixes []ListCommonPrefix) error {
+	var err error
+	for _, field := range fields {
+		if *field, err = url.QueryUnescape(*field); err != nil {
+			return err
 		}
 	}
-	var bodyNum = len(bodyList)
-	if bodyNum < this.threadMaxNum {
-		this.threadMaxNum = bodyNum
+	for i := range contents {
+		if contents[i].Key, err = url.QueryUnescape(contents[i].Key); err != nil {
+			return err
+		}
 	}
-	var queueMaxSize = make(chan bool, this.threadMaxNum)
-	var deletePercent = make(chan bool)
-	var deleteDone = make(chan struct{})
-
-	//实时进度
-	go func() {
-		finishNum := 0
-		for {
-			_, ok := <-deletePercent
-			if !ok {
-				close(deleteDone)
-				break
-			}
-			finishNum++
-			fmt.Printf("\r%.0f%%", float64(finishNum)/float64(bodyNum)*100)
+	for i := range commonPrefixes {
+		if commonPrefixes[i].Prefix, err = url.QueryUnescape(commonPrefixes[i].Prefix); err != nil {
+			return err
 		}
-	}()
+	}
+	return nil
+}
 
-	fileNum := 0
-	for {
-		if fileNum >= bodyNum {
-			break
-		}
-		wg.Add(1)
-		queueMaxSize <- true
-		go func(fileNum int, body string) {
-			defer wg.Done()
-			addr := "http://" + bucket + this.host + "/?delete"
-			method := "POST"
-			date := time.Unix(time.Now().Unix()-8*3600, 0).Format(this.dateTimeGMT)
-			contentLength := strconv.Itoa(len(body))
-			contentMd5 := this.base64(this.md5Byte([]byte(body)))
-			headers := map[string]string{
-				"Content-Md5": contentMd5 + "\n",
-				"Date":        date,
-			}
-			headers["Authorization"] = this.sign(method, headers, bucket, "?delete")
-			headers["Content-Length"] = contentLength
-			headers["Content-Md5"] = strings.TrimRight(headers["Content-Md5"], "\n")
-			isDeleteSuccess := false
-			for i := 0; i < this.maxRetryNum; i++ {
-				res, err := this.curl(addr, method, headers, []byte(body))
-				if err != nil {
-					continue
-				}
-				isDeleteSuccess = true
-				if res["StatusCode"] == "200" {
-					atomic.AddInt64(&tmpFinish, int64(bodyListNum[fileNum]))
-				}
-				break
-			}
-			if !isDeleteSuccess {
-				fmt.Printf("\nDelete File Fail FileNum:%d\n", fileNum)
-				os.Exit(2)
-			}
-			deletePercent <- true
-			<-queueMaxSize
-		}(fileNum, bodyList[fileNum])
-		fileNum++
+// 单次Copy最大支持1GB，超过的object改用CopyLargeFile分片复制
+const copyLargeThreshold = 1024 * 1024 * 1024
+
+func (this *Client) CopyAllObject(bucket, prefix, source string, options map[string]string) (map[string]int, error) {
+	plan, err := this.CopyAllObjectPlan(bucket, prefix, source, options)
+	if err != nil {
+		return nil, err
 	}
-	wg.Wait()
-	close(deletePercent)
-	<-deleteDone
-	finish := int(atomic.LoadInt64(&tmpFinish))
-	return map[string]int{"total": total, "finish": finish}, nil
+	return this.ExecutePlan(plan, options)
 }
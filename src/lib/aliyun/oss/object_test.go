@@ -0,0 +1,46 @@
+package oss
+
+import (
+	"testing"
+)
+
+func TestCopyReplaceMetadata(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	srv.PutObject("bucket", "src.bin", []byte("data"), map[string]string{
+		"Content-Type":        "image/png",
+		"Content-Disposition": "inline",
+		"X-Oss-Meta-Owner":    "alice",
+	})
+
+	//不指定元数据时保留源object的元数据
+	res, err := client.Copy("bucket", "keep.bin", "/bucket/src.bin", map[string]string{})
+	if err = client.checkStatus(res, err, "200"); err != nil {
+		t.Fatal(err)
+	}
+	object, _ := srv.Object("bucket", "keep.bin")
+	if object.Headers["Content-Type"] != "image/png" || object.Headers["X-Oss-Meta-Owner"] != "alice" {
+		t.Fatalf("unexpected headers %v", object.Headers)
+	}
+
+	//签名包含Content-Type，由osstest校验
+	res, err = client.Copy("bucket", "replace.bin", "/bucket/src.bin", map[string]string{"x-oss-meta-team": "oss", "x-oss-tagging": "k=v"})
+	if err = client.checkStatus(res, err, "200"); err != nil {
+		t.Fatal(err)
+	}
+	object, _ = srv.Object("bucket", "replace.bin")
+	for k, want := range map[string]string{"Content-Type": "image/png", "Content-Disposition": "inline", "X-Oss-Meta-Team": "oss", "X-Oss-Meta-Owner": "", "X-Oss-Tagging": "k=v"} {
+		if object.Headers[k] != want {
+			t.Fatalf("%s = %q, want %q", k, object.Headers[k], want)
+		}
+	}
+
+	res, err = client.Copy("bucket", "typed.bin", "/bucket/src.bin", map[string]string{"x-oss-meta-team": "oss", "content-type": "text/plain"})
+	if err = client.checkStatus(res, err, "200"); err != nil {
+		t.Fatal(err)
+	}
+	object, _ = srv.Object("bucket", "typed.bin")
+	if object.Headers["Content-Type"] != "text/plain" {
+		t.Fatalf("Content-Type = %q, want text/plain", object.Headers["Content-Type"])
+	}
+}
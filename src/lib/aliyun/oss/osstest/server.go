@@ -0,0 +1,569 @@
+// Package osstest提供内存中的OSS模拟服务，用于测试oss.Client
+//
+// 模拟服务独立实现请求签名校验，签名错误时返回403 SignatureDoesNotMatch
+package osstest
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"hash/crc64"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	AccessKeyId     = "test-access-id"
+	AccessKeySecret = "test-access-key"
+)
+
+// 参与签名的子资源
+var subResources = map[string]bool{
+	"acl": true, "uploads": true, "location": true, "cors": true, "logging": true, "website": true,
+	"referer": true, "lifecycle": true, "delete": true, "append": true, "tagging": true, "objectMeta": true,
+	"uploadId": true, "partNumber": true, "position": true, "symlink": true, "restore": true,
+	"versionId": true, "versioning": true, "versions": true, "policy": true, "inventory": true,
+	"replication": true, "replicationProgress": true, "bucketInfo": true, "continuation-token": true,
+	"encryption": true,
+}
+
+type Object struct {
+	Data         []byte
+	Headers      map[string]string
+	LastModified time.Time
+}
+
+func (this *Object) ETag() string {
+	if etag := this.Headers["Etag"]; etag != "" {
+		return etag
+	}
+	return fmt.Sprintf(`"%X"`, md5.Sum(this.Data))
+}
+
+type upload struct {
+	bucket  string
+	key     string
+	headers map[string]string
+	parts   map[int][]byte
+}
+
+// 记录的请求，Header为签名校验时使用的请求头
+type Request struct {
+	Method string
+	Bucket string
+	Key    string
+	Query  url.Values
+	Header http.Header
+}
+
+type Server struct {
+	Host string
+
+	//处理请求前调用，可用于模拟并发修改
+	Hook func(r *Request)
+	//批量删除时返回错误的key
+	DeleteErrors map[string]string
+
+	server    *httptest.Server
+	transport http.RoundTripper
+
+	lock     sync.Mutex
+	objects  map[string]*Object
+	uploads  map[string]*upload
+	requests []Request
+	uploadId int
+}
+
+// 启动模拟服务并让http.DefaultTransport的所有连接指向它，Close时恢复
+func NewServer() *Server {
+	srv := &Server{
+		Host:         "oss-test.aliyuncs.com",
+		DeleteErrors: map[string]string{},
+		objects:      map[string]*Object{},
+		uploads:      map[string]*upload{},
+	}
+	srv.server = httptest.NewServer(http.HandlerFunc(srv.serveHTTP))
+	addr := srv.server.Listener.Addr().String()
+	srv.transport = http.DefaultTransport
+	http.DefaultTransport = &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}
+	return srv
+}
+
+func (this *Server) Close() {
+	http.DefaultTransport = this.transport
+	this.server.Close()
+}
+
+func (this *Server) PutObject(bucket, key string, data []byte, headers map[string]string) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.putObject(bucket, key, data, headers)
+}
+
+func (this *Server) putObject(bucket, key string, data []byte, headers map[string]string) *Object {
+	object := &Object{Data: data, Headers: map[string]string{}, LastModified: time.Now().UTC()}
+	for k, v := range headers {
+		object.Headers[http.CanonicalHeaderKey(k)] = v
+	}
+	this.objects[bucket+"/"+key] = object
+	return object
+}
+
+// 返回object的副本
+func (this *Server) Object(bucket, key string) (*Object, bool) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	object, ok := this.objects[bucket+"/"+key]
+	if !ok {
+		return nil, false
+	}
+	copied := *object
+	return &copied, true
+}
+
+// bucket下按key排序的所有object
+func (this *Server) Keys(bucket string) []string {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.keys(bucket, "")
+}
+
+func (this *Server) keys(bucket, prefix string) []string {
+	keys := make([]string, 0)
+	for name := range this.objects {
+		if strings.HasPrefix(name, bucket+"/"+prefix) {
+			keys = append(keys, strings.TrimPrefix(name, bucket+"/"))
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (this *Server) Requests() []Request {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return append([]Request{}, this.requests...)
+}
+
+func (this *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket := strings.TrimSuffix(strings.TrimSuffix(r.Host, "."+this.Host), this.Host)
+	req := Request{Method: r.Method, Bucket: bucket, Key: strings.TrimPrefix(r.URL.Path, "/"), Query: r.URL.Query(), Header: r.Header}
+	body, _ := ioutil.ReadAll(r.Body)
+	if hook := this.Hook; hook != nil {
+		hook(&req)
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.requests = append(this.requests, req)
+
+	if expected := stringToSign(r, bucket, req.Key); r.Header.Get("Authorization") != authorization(expected) {
+		writeError(w, http.StatusForbidden, "SignatureDoesNotMatch", fmt.Sprintf("%q", expected))
+		return
+	}
+	if md5Header := r.Header.Get("Content-Md5"); md5Header != "" {
+		sum := md5.Sum(body)
+		if md5Header != base64.StdEncoding.EncodeToString(sum[:]) {
+			writeError(w, http.StatusBadRequest, "InvalidDigest", md5Header)
+			return
+		}
+	}
+	query := r.URL.Query()
+	switch {
+	case r.Method == "GET" && req.Key == "":
+		this.list(w, bucket, query)
+	case r.Method == "POST" && req.Key == "" && hasQuery(query, "delete"):
+		this.deleteObjects(w, bucket, query, body)
+	case r.Method == "POST" && hasQuery(query, "uploads"):
+		this.initUpload(w, bucket, req.Key, r.Header)
+	case r.Method == "PUT" && hasQuery(query, "uploadId"):
+		this.uploadPart(w, r, query, body)
+	case r.Method == "POST" && hasQuery(query, "uploadId"):
+		this.completeUpload(w, bucket, req.Key, query, body)
+	case r.Method == "PUT" && r.Header.Get("x-oss-copy-source") != "":
+		this.copyObject(w, r, bucket, req.Key)
+	case r.Method == "PUT":
+		object := this.putObject(bucket, req.Key, body, objectHeaders(r.Header))
+		w.Header().Set("ETag", object.ETag())
+		w.WriteHeader(http.StatusOK)
+	case r.Method == "HEAD" || r.Method == "GET":
+		this.getObject(w, r, bucket, req.Key)
+	case r.Method == "DELETE":
+		delete(this.objects, bucket+"/"+req.Key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method)
+	}
+}
+
+func hasQuery(query url.Values, name string) bool {
+	_, ok := query[name]
+	return ok
+}
+
+// 存储的object头：Content-Type、Content-Disposition、x-oss-meta-*、x-oss-storage-class、x-oss-tagging等
+func objectHeaders(header http.Header) map[string]string {
+	headers := map[string]string{}
+	for k := range header {
+		name := strings.ToLower(k)
+		if strings.HasPrefix(name, "x-oss-meta-") || name == "x-oss-storage-class" || name == "x-oss-tagging" ||
+			name == "content-type" || name == "content-disposition" || name == "cache-control" || name == "content-encoding" {
+			headers[http.CanonicalHeaderKey(k)] = header.Get(k)
+		}
+	}
+	return headers
+}
+
+func (this *Server) getObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	object, ok := this.objects[bucket+"/"+key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchKey", key)
+		return
+	}
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && ifMatch != object.ETag() {
+		writeError(w, http.StatusPreconditionFailed, "PreconditionFailed", "If-Match")
+		return
+	}
+	for k, v := range object.Headers {
+		if k != "Etag" {
+			w.Header().Set(k, v)
+		}
+	}
+	w.Header().Set("ETag", object.ETag())
+	w.Header().Set("Last-Modified", object.LastModified.Format(http.TimeFormat))
+	w.Header().Set("X-Oss-Hash-Crc64ecma", strconv.FormatUint(crc64.Checksum(object.Data, crc64.MakeTable(crc64.ECMA)), 10))
+	data := object.Data
+	status := http.StatusOK
+	if start, end, ok := parseRange(r.Header.Get("Range"), int64(len(data))); ok {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+		data = data[start : end+1]
+		status = http.StatusPartialContent
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(status)
+	if r.Method == "GET" {
+		w.Write(data)
+	}
+}
+
+// 只支持bytes=start-[end]，范围无效时按整个object返回
+func parseRange(value string, size int64) (int64, int64, bool) {
+	if !strings.HasPrefix(value, "bytes=") {
+		return 0, 0, false
+	}
+	tmp := strings.SplitN(strings.TrimPrefix(value, "bytes="), "-", 2)
+	if len(tmp) != 2 {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(tmp[0], 10, 64)
+	if err != nil || start >= size {
+		return 0, 0, false
+	}
+	end := size - 1
+	if tmp[1] != "" {
+		if end, err = strconv.ParseInt(tmp[1], 10, 64); err != nil || end < start {
+			return 0, 0, false
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+	return start, end, true
+}
+
+func (this *Server) copyObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	source := strings.TrimPrefix(r.Header.Get("x-oss-copy-source"), "/")
+	sourceObject, ok := this.objects[source]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchKey", source)
+		return
+	}
+	headers := map[string]string{}
+	for k, v := range sourceObject.Headers {
+		if k != "Etag" {
+			headers[k] = v
+		}
+	}
+	requestHeaders := objectHeaders(r.Header)
+	if strings.ToUpper(r.Header.Get("x-oss-metadata-directive")) == "REPLACE" {
+		//替换元数据时不保留源object的元数据和Content-Type
+		for k := range headers {
+			if strings.HasPrefix(k, "X-Oss-Meta-") || k == "Content-Type" || k == "Content-Disposition" {
+				delete(headers, k)
+			}
+		}
+		for k, v := range requestHeaders {
+			headers[k] = v
+		}
+	}
+	if v, ok := requestHeaders["X-Oss-Storage-Class"]; ok {
+		headers["X-Oss-Storage-Class"] = v
+	}
+	if strings.ToLower(r.Header.Get("x-oss-tagging-directive")) == "replace" {
+		headers["X-Oss-Tagging"] = requestHeaders["X-Oss-Tagging"]
+	}
+	object := this.putObject(bucket, key, append([]byte{}, sourceObject.Data...), headers)
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "<CopyObjectResult><LastModified>%s</LastModified><ETag>%s</ETag></CopyObjectResult>",
+		object.LastModified.Format(time.RFC3339), object.ETag())
+}
+
+type listResult struct {
+	XMLName        xml.Name       `xml:"ListBucketResult"`
+	Name           string         `xml:"Name"`
+	Prefix         string         `xml:"Prefix"`
+	Marker         string         `xml:"Marker"`
+	MaxKeys        int            `xml:"MaxKeys"`
+	Delimiter      string         `xml:"Delimiter"`
+	EncodingType   string         `xml:"EncodingType,omitempty"`
+	IsTruncated    bool           `xml:"IsTruncated"`
+	NextMarker     string         `xml:"NextMarker,omitempty"`
+	Contents       []listContents `xml:"Contents"`
+	CommonPrefixes []listPrefix   `xml:"CommonPrefixes"`
+}
+
+type listContents struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Type         string `xml:"Type"`
+	Size         int    `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type listPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+func (this *Server) list(w http.ResponseWriter, bucket string, query url.Values) {
+	prefix, delimiter, marker := query.Get("prefix"), query.Get("delimiter"), query.Get("marker")
+	maxKeys := 100
+	if query.Get("max-keys") != "" {
+		maxKeys, _ = strconv.Atoi(query.Get("max-keys"))
+	}
+	encode := func(s string) string { return s }
+	result := listResult{Name: bucket, Prefix: prefix, Marker: marker, MaxKeys: maxKeys, Delimiter: delimiter}
+	if query.Get("encoding-type") == "url" {
+		result.EncodingType = "url"
+		encode = url.QueryEscape
+		result.Prefix, result.Marker, result.Delimiter = encode(prefix), encode(marker), encode(delimiter)
+	}
+	count := 0
+	lastPrefix := ""
+	for _, key := range this.keys(bucket, prefix) {
+		if key <= marker {
+			continue
+		}
+		name := key
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				name = key[:len(prefix)+i+len(delimiter)]
+			}
+		}
+		if name == lastPrefix {
+			continue
+		}
+		if count == maxKeys {
+			result.IsTruncated = true
+			break
+		}
+		count++
+		result.NextMarker = encode(name)
+		if name != key {
+			lastPrefix = name
+			result.CommonPrefixes = append(result.CommonPrefixes, listPrefix{Prefix: encode(name)})
+			continue
+		}
+		object := this.objects[bucket+"/"+key]
+		result.Contents = append(result.Contents, listContents{
+			Key:          encode(key),
+			LastModified: object.LastModified.Format("2006-01-02T15:04:05.000Z"),
+			ETag:         object.ETag(),
+			Type:         "Normal",
+			Size:         len(object.Data),
+			StorageClass: "Standard",
+		})
+	}
+	if !result.IsTruncated {
+		result.NextMarker = ""
+	}
+	writeXML(w, result)
+}
+
+type deleteRequest struct {
+	Quiet   bool `xml:"Quiet"`
+	Objects []struct {
+		Key string `xml:"Key"`
+	} `xml:"Object"`
+}
+
+func (this *Server) deleteObjects(w http.ResponseWriter, bucket string, query url.Values, body []byte) {
+	var request deleteRequest
+	if err := xml.Unmarshal(body, &request); err != nil {
+		writeError(w, http.StatusBadRequest, "MalformedXML", err.Error())
+		return
+	}
+	encode := func(s string) string { return s }
+	var res bytes.Buffer
+	if query.Get("encoding-type") == "url" {
+		encode = url.QueryEscape
+		res.WriteString("<EncodingType>url</EncodingType>")
+	}
+	failed := false
+	for _, v := range request.Objects {
+		if code, ok := this.DeleteErrors[v.Key]; ok {
+			failed = true
+			fmt.Fprintf(&res, "<Error><Key>%s</Key><Code>%s</Code><Message>%s</Message></Error>", encode(v.Key), code, code)
+			continue
+		}
+		delete(this.objects, bucket+"/"+v.Key)
+		if !request.Quiet {
+			fmt.Fprintf(&res, "<Deleted><Key>%s</Key></Deleted>", encode(v.Key))
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	//quiet模式全部删除成功时OSS不返回body
+	if request.Quiet && !failed {
+		return
+	}
+	fmt.Fprintf(w, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<DeleteResult>%s</DeleteResult>", res.String())
+}
+
+func (this *Server) initUpload(w http.ResponseWriter, bucket, key string, header http.Header) {
+	this.uploadId++
+	uploadId := strconv.Itoa(this.uploadId)
+	this.uploads[uploadId] = &upload{bucket: bucket, key: key, headers: objectHeaders(header), parts: map[int][]byte{}}
+	fmt.Fprintf(w, "<InitiateMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><UploadId>%s</UploadId></InitiateMultipartUploadResult>",
+		bucket, key, uploadId)
+}
+
+func (this *Server) uploadPart(w http.ResponseWriter, r *http.Request, query url.Values, body []byte) {
+	upload, ok := this.uploads[query.Get("uploadId")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", query.Get("uploadId"))
+		return
+	}
+	partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+	if source := r.Header.Get("x-oss-copy-source"); source != "" {
+		sourceObject, ok := this.objects[strings.TrimPrefix(source, "/")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchKey", source)
+			return
+		}
+		body = sourceObject.Data
+		if start, end, ok := parseRange(r.Header.Get("x-oss-copy-source-range"), int64(len(body))); ok {
+			body = body[start : end+1]
+		}
+	}
+	upload.parts[partNumber] = append([]byte{}, body...)
+	etag := fmt.Sprintf(`"%X"`, md5.Sum(body))
+	w.Header().Set("ETag", etag)
+	w.WriteHeader(http.StatusOK)
+	if r.Header.Get("x-oss-copy-source") != "" {
+		fmt.Fprintf(w, "<CopyPartResult><ETag>%s</ETag></CopyPartResult>", etag)
+	}
+}
+
+func (this *Server) completeUpload(w http.ResponseWriter, bucket, key string, query url.Values, body []byte) {
+	upload, ok := this.uploads[query.Get("uploadId")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", query.Get("uploadId"))
+		return
+	}
+	var request struct {
+		Parts []struct {
+			PartNumber int `xml:"PartNumber"`
+		} `xml:"Part"`
+	}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		writeError(w, http.StatusBadRequest, "MalformedXML", err.Error())
+		return
+	}
+	data := make([]byte, 0)
+	for _, part := range request.Parts {
+		data = append(data, upload.parts[part.PartNumber]...)
+	}
+	headers := map[string]string{}
+	for k, v := range upload.headers {
+		headers[k] = v
+	}
+	sum := md5.Sum(data)
+	headers["Etag"] = fmt.Sprintf(`"%X-%d"`, sum[:], len(request.Parts))
+	object := this.putObject(bucket, key, data, headers)
+	delete(this.uploads, query.Get("uploadId"))
+	fmt.Fprintf(w, "<CompleteMultipartUploadResult><Location>http://%s.%s/%s</Location><Bucket>%s</Bucket><Key>%s</Key><ETag>%s</ETag></CompleteMultipartUploadResult>",
+		bucket, this.Host, key, bucket, key, object.ETag())
+}
+
+func writeXML(w http.ResponseWriter, v interface{}) {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "InternalError", err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message><RequestId>test</RequestId></Error>", code, message)
+}
+
+// OSS V1签名：VERB\nContent-MD5\nContent-Type\nDate\nCanonicalizedOSSHeaders+CanonicalizedResource
+func stringToSign(r *http.Request, bucket, key string) string {
+	sign := r.Method + "\n" + r.Header.Get("Content-Md5") + "\n" + r.Header.Get("Content-Type") + "\n" + r.Header.Get("Date") + "\n"
+	//按header名排序，x-oss-tagging排在x-oss-tagging-directive之前
+	ossHeaders := make([]string, 0)
+	for k := range r.Header {
+		if name := strings.ToLower(k); strings.HasPrefix(name, "x-oss-") {
+			ossHeaders = append(ossHeaders, name)
+		}
+	}
+	sort.Strings(ossHeaders)
+	for _, name := range ossHeaders {
+		sign += name + ":" + r.Header.Get(name) + "\n"
+	}
+	resource := "/"
+	if bucket != "" {
+		resource += bucket + "/" + key
+	}
+	params := make([]string, 0)
+	for k, v := range r.URL.Query() {
+		if !subResources[k] {
+			continue
+		}
+		if v[0] == "" {
+			params = append(params, k)
+		} else {
+			params = append(params, k+"="+v[0])
+		}
+	}
+	sort.Strings(params)
+	if len(params) > 0 {
+		resource += "?" + strings.Join(params, "&")
+	}
+	return sign + resource
+}
+
+func authorization(stringToSign string) string {
+	h := hmac.New(sha1.New, []byte(AccessKeySecret))
+	h.Write([]byte(stringToSign))
+	return "OSS " + AccessKeyId + ":" + base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
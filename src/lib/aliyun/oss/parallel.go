@@ -0,0 +1,205 @@
+package oss
+
+import (
+	"sort"
+	"sync"
+)
+
+// 分片最多向下展开的目录层数
+const listShardMaxDepth = 3
+
+// 每个worker对应的分片数，分片越多各worker负载越均衡
+const listShardsPerWorker = 4
+
+// 一个分片为一个目录前缀(不带delimiter从marker之后完整列举)，或分片探测时已列出的一段连续object
+type listShard struct {
+	key     string
+	prefix  string
+	marker  string
+	objects []ListObjectContents
+	items   chan []ListObjectContents
+	err     error
+}
+
+// 并发列举prefix下的所有object，结果按key顺序返回
+//
+// 先用delimiter="/"逐层列举目录，把key空间切分为多个互不相交的目录分片，
+// 再由workers个goroutine并发列举各分片，按分片顺序合并输出。
+// objects遍历结束后关闭，出错时errs收到一个错误；
+// 调用方提前结束读取时关闭done，后台列举停止后关闭objects和errs，done为nil时不能取消
+func (this *Client) ListParallel(bucket, prefix string, workers int, done <-chan struct{}) (<-chan ObjectIteratorItem, <-chan error) {
+	objects := make(chan ObjectIteratorItem, 1000)
+	errs := make(chan error, 1)
+	if workers < 1 {
+		workers = 1
+	}
+	go func() {
+		defer close(objects)
+		defer close(errs)
+		shards, err := this.listShards(bucket, prefix, workers*listShardsPerWorker)
+		if err != nil {
+			errs <- err
+			return
+		}
+		//stop在返回时关闭，通知worker退出，返回前等待worker结束
+		stop := make(chan struct{})
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		defer close(stop)
+
+		//按顺序分发分片，保证最靠前的未完成分片总有worker在列举
+		jobs := make(chan *listShard)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for shard := range jobs {
+					this.listShard(bucket, shard, stop)
+				}
+			}()
+		}
+		go func() {
+			defer close(jobs)
+			for _, shard := range shards {
+				if shard.items == nil {
+					continue
+				}
+				select {
+				case jobs <- shard:
+				case <-stop:
+					return
+				}
+			}
+		}()
+
+		send := func(page []ListObjectContents) bool {
+			for _, v := range page {
+				select {
+				case objects <- ObjectIteratorItem{ListObjectContents: v}:
+				case <-done:
+					return false
+				}
+			}
+			return true
+		}
+		for _, shard := range shards {
+			if shard.items == nil {
+				if !send(shard.objects) {
+					return
+				}
+				continue
+			}
+			for page := range shard.items {
+				if !send(page) {
+					return
+				}
+			}
+			if shard.err != nil {
+				errs <- shard.err
+				return
+			}
+		}
+	}()
+	return objects, errs
+}
+
+// 列举单个目录分片，结果按页写入shard.items
+func (this *Client) listShard(bucket string, shard *listShard, done <-chan struct{}) {
+	defer close(shard.items)
+	it := this.NewObjectIterator(bucket, map[string]string{"prefix": shard.prefix, "marker": shard.marker})
+	defer it.Close()
+	page := make([]ListObjectContents, 0, 1000)
+	for it.Next() {
+		page = append(page, it.Object().ListObjectContents)
+		if len(page) < 1000 {
+			continue
+		}
+		select {
+		case shard.items <- page:
+		case <-done:
+			return
+		}
+		page = make([]ListObjectContents, 0, 1000)
+	}
+	if len(page) > 0 {
+		select {
+		case shard.items <- page:
+		case <-done:
+			return
+		}
+	}
+	shard.err = it.Err()
+}
+
+// 逐层展开目录直到分片数达到maxShards或到达最大层数，返回按key排序的分片
+//
+// 每个目录只列举第一页：其中的子目录继续展开，object按子目录切分为连续的静态分片，
+// 第一页未列举完时，该目录第一页之后的部分作为一个不带delimiter的分片流式列举
+func (this *Client) listShards(bucket, prefix string, maxShards int) ([]*listShard, error) {
+	shards := make([]*listShard, 0)
+	//需要worker列举的分片数
+	streaming := 0
+	pending := []string{prefix}
+	for depth := 0; depth < listShardMaxDepth && len(pending) > 0; depth++ {
+		next := make([]string, 0)
+		for i, dir := range pending {
+			//分片数已足够，剩余目录直接作为分片
+			if streaming+len(next)+len(pending)-i >= maxShards {
+				next = append(next, pending[i:]...)
+				break
+			}
+			list, err := this.listShardPage(bucket, dir)
+			if err != nil {
+				return nil, err
+			}
+			items := pageItems(list)
+			objects := make([]ListObjectContents, 0)
+			for _, v := range items {
+				if !v.IsPrefix {
+					objects = append(objects, v.ListObjectContents)
+					continue
+				}
+				if len(objects) > 0 {
+					shards = append(shards, &listShard{key: objects[0].Key, objects: objects})
+					objects = make([]ListObjectContents, 0)
+				}
+				next = append(next, v.Key)
+			}
+			if len(objects) > 0 {
+				shards = append(shards, &listShard{key: objects[0].Key, objects: objects})
+			}
+			if list.IsTruncated == "true" && len(items) > 0 {
+				//marker跳过第一页最后一个子目录下的所有key，U+10FFFF大于任何合法UTF-8字符
+				marker := items[len(items)-1].Key
+				if items[len(items)-1].IsPrefix {
+					marker += "\U0010FFFF"
+				}
+				shards = append(shards, &listShard{key: marker, prefix: dir, marker: marker, items: make(chan []ListObjectContents, 10)})
+				streaming++
+			}
+		}
+		pending = next
+		if streaming+len(pending) >= maxShards {
+			break
+		}
+	}
+	for _, dir := range pending {
+		shards = append(shards, &listShard{key: dir, prefix: dir, items: make(chan []ListObjectContents, 10)})
+	}
+	//各分片的key范围互不相交，按起始key排序即为整体key顺序
+	sort.Slice(shards, func(i, j int) bool { return shards[i].key < shards[j].key })
+	return shards, nil
+}
+
+// 分片探测时只列举目录的第一页
+func (this *Client) listShardPage(bucket, dir string) (*ListObjectResult, error) {
+	var list *ListObjectResult
+	var err error
+	for i := 0; i < this.maxRetryNum; i++ {
+		list, err = this.ListObject(bucket, map[string]string{"prefix": dir, "delimiter": "/", "max-keys": "1000"})
+		if err == nil {
+			break
+		}
+	}
+	return list, err
+}
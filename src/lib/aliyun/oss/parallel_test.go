@@ -0,0 +1,105 @@
+package oss
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListParallel(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	//平铺的大目录
+	for i := 0; i < 2500; i++ {
+		srv.PutObject("bucket", fmt.Sprintf("flat/%04d", i), []byte("x"), nil)
+	}
+	//object和子目录交错
+	for _, key := range []string{"mix/a.txt", "mix/d1/x", "mix/d1/y", "mix/m.txt", "mix/d2/z", "mix/z.txt", "top.txt"} {
+		srv.PutObject("bucket", key, []byte("x"), nil)
+	}
+	//第一页以子目录结束且未列举完
+	for i := 0; i < 1200; i++ {
+		srv.PutObject("bucket", fmt.Sprintf("wide/%04d/x", i), []byte("x"), nil)
+		srv.PutObject("bucket", fmt.Sprintf("wide/%04d/y", i), []byte("x"), nil)
+	}
+
+	for _, workers := range []int{1, 4, 50} {
+		objects, errs := client.ListParallel("bucket", "", workers, nil)
+		keys := make([]string, 0)
+		for v := range objects {
+			keys = append(keys, v.Key)
+		}
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(keys, srv.Keys("bucket")) {
+			t.Fatalf("workers %d: listed %d keys, want %d in key order", workers, len(keys), len(srv.Keys("bucket")))
+		}
+	}
+
+	//分片探测对每个目录只列举第一页
+	for _, r := range srv.Requests() {
+		if r.Query.Get("delimiter") != "" && r.Query.Get("marker") != "" {
+			t.Fatalf("discovery listed past the first page: prefix %q marker %q", r.Query.Get("prefix"), r.Query.Get("marker"))
+		}
+	}
+}
+
+func TestListParallelCancel(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	for i := 0; i < 5000; i++ {
+		srv.PutObject("bucket", fmt.Sprintf("d%d/%04d", i%5, i), []byte("x"), nil)
+	}
+
+	//读取一个后取消，objects和errs应当关闭，不会读完全部结果
+	done := make(chan struct{})
+	objects, errs := client.ListParallel("bucket", "", 4, done)
+	<-objects
+	close(done)
+	count := 0
+	timeout := time.After(5 * time.Second)
+	for closed := false; !closed; {
+		select {
+		case _, ok := <-objects:
+			closed = !ok
+			count++
+		case <-timeout:
+			t.Fatal("objects not closed after cancel")
+		}
+	}
+	if count >= 5000 {
+		t.Fatal("listing was not cancelled")
+	}
+	select {
+	case <-errs:
+	case <-timeout:
+		t.Fatal("errs not closed after cancel")
+	}
+
+}
+
+func TestCopyAllObjectPlanFlattenConflict(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	srv.PutObject("bucket", "a/k", []byte("x"), nil)
+	srv.PutObject("bucket", "b/k", []byte("x"), nil)
+	for i := 0; i < 40000; i++ {
+		srv.PutObject("bucket", fmt.Sprintf("big/%05d", i), []byte("x"), nil)
+	}
+
+	//冲突出现在最前面，取消列举而不是读完big/下的40页
+	if _, err := client.CopyAllObjectPlan("bucket", "flat/", "/bucket/", map[string]string{"flatten": "true"}); err == nil {
+		t.Fatal("expected flatten conflict")
+	}
+	lists := 0
+	for _, r := range srv.Requests() {
+		if r.Method == "GET" && r.Query.Get("prefix") == "big/" {
+			lists++
+		}
+	}
+	if lists >= 30 {
+		t.Fatalf("flatten conflict listed %d pages of big/", lists)
+	}
+}
@@ -0,0 +1,439 @@
+package oss
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	PlanUpload      = "upload"
+	PlanDownload    = "download"
+	PlanCopy        = "copy"
+	PlanMove        = "move"
+	PlanDelete      = "delete"
+	PlanDeleteLocal = "delete-local"
+	PlanMkdir       = "mkdir"
+	PlanSkip        = "skip"
+)
+
+// 批量操作的执行计划，先计算出全部操作再执行
+type Plan struct {
+	Actions []PlanAction `json:"actions"`
+}
+
+// Bucket/Key为目标object(delete时为被删除的object)，
+// Source为copy、move的源object(/bucket/object)，LocalFile为上传、下载的本地文件
+type PlanAction struct {
+	Action    string `json:"action"`
+	Bucket    string `json:"bucket,omitempty"`
+	Key       string `json:"key,omitempty"`
+	Source    string `json:"source,omitempty"`
+	LocalFile string `json:"localfile,omitempty"`
+	Size      int64  `json:"size"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func (this *Plan) add(action PlanAction) {
+	this.Actions = append(this.Actions, action)
+}
+
+// 指定类型的操作数量
+func (this *Plan) Count(action string) int {
+	count := 0
+	for _, v := range this.Actions {
+		if v.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// 指定类型的操作涉及的总字节数
+func (this *Plan) Size(action string) int64 {
+	size := int64(0)
+	for _, v := range this.Actions {
+		if v.Action == action {
+			size += v.Size
+		}
+	}
+	return size
+}
+
+// 并发执行计划中的操作，options：thread_num、sse、sse-key-id
+func (this *Client) ExecutePlan(plan *Plan, options map[string]string) (map[string]int, error) {
+	var wg sync.WaitGroup
+	total := len(plan.Actions)
+	workNum := total - plan.Count(PlanSkip)
+	tmpSkip := int64(0)
+	tmpFinish := int64(0)
+	threadNum := this.threadMaxNum
+	if options["thread_num"] != "" {
+		tmpThreadNum, err := strconv.Atoi(options["thread_num"])
+		if err == nil && tmpThreadNum <= this.threadMaxNum && tmpThreadNum >= this.threadMinNum {
+			threadNum = tmpThreadNum
+		}
+	}
+	largeActions := make([]PlanAction, 0)
+	var queueMaxSize = make(chan bool, threadNum)
+	var actionPercent = make(chan bool)
+	var actionDone = make(chan struct{})
+
+	//实时进度
+	go func() {
+		finishNum := 0
+		for {
+			_, ok := <-actionPercent
+			if !ok {
+				close(actionDone)
+				break
+			}
+			finishNum++
+			fmt.Printf("\r%.0f%%", float64(finishNum)/float64(workNum)*100)
+		}
+	}()
+
+	run := func(action PlanAction) {
+		var err error
+		for i := 0; i < this.maxRetryNum; i++ {
+			if err = this.executeAction(action, options); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			target := action.LocalFile
+			if action.Key != "" {
+				target = "oss://" + action.Bucket + "/" + action.Key
+			}
+			fmt.Printf("\n%s Fail,%s:%s\n", action.Action, target, err)
+		} else {
+			atomic.AddInt64(&tmpFinish, 1)
+		}
+		actionPercent <- true
+	}
+	for _, action := range plan.Actions {
+		if action.Action == PlanSkip {
+			atomic.AddInt64(&tmpSkip, 1)
+			continue
+		}
+		if (action.Action == PlanCopy || action.Action == PlanMove) && action.Size > copyLargeThreshold ||
+			action.Action == PlanUpload && action.Size > uploadLargeThreshold {
+			largeActions = append(largeActions, action)
+			continue
+		}
+		wg.Add(1)
+		queueMaxSize <- true
+		go func(action PlanAction) {
+			defer wg.Done()
+			run(action)
+			<-queueMaxSize
+		}(action)
+	}
+	wg.Wait()
+	//大文件上传、复制和移动使用分片，在其他操作完成后逐个执行，分片本身是并发的
+	for _, action := range largeActions {
+		run(action)
+	}
+	close(actionPercent)
+	<-actionDone
+	skip := int(atomic.LoadInt64(&tmpSkip))
+	finish := int(atomic.LoadInt64(&tmpFinish))
+	return map[string]int{"total": total, "skip": skip, "finish": finish}, nil
+}
+
+func (this *Client) executeAction(action PlanAction, options map[string]string) error {
+	switch action.Action {
+	case PlanUpload:
+		if action.Size > uploadLargeThreshold {
+			_, err := this.UploadLargeFile(action.LocalFile, action.Bucket, action.Key, map[string]string{
+				"thread_num": options["thread_num"],
+				"sse":        options["sse"],
+				"sse-key-id": options["sse-key-id"],
+			})
+			return err
+		}
+		body, err := ioutil.ReadFile(action.LocalFile)
+		if err != nil {
+			return err
+		}
+		res, err := this.Put(body, action.Bucket, action.Key, map[string]string{
+			"sse":        options["sse"],
+			"sse-key-id": options["sse-key-id"],
+		})
+		return this.checkStatus(res, err, "200")
+	case PlanCopy:
+		if action.Size > copyLargeThreshold {
+			_, err := this.CopyLargeFile(action.Bucket, action.Key, action.Source, map[string]string{
+				"thread_num": options["thread_num"],
+				"sse":        options["sse"],
+				"sse-key-id": options["sse-key-id"],
+			})
+			return err
+		}
+		res, err := this.Copy(action.Bucket, action.Key, action.Source, map[string]string{
+			"sse":        options["sse"],
+			"sse-key-id": options["sse-key-id"],
+		})
+		return this.checkStatus(res, err, "200")
+	case PlanMove:
+		_, err := this.Move(action.Bucket, action.Key, action.Source, map[string]string{
+			"thread_num": options["thread_num"],
+			"sse":        options["sse"],
+			"sse-key-id": options["sse-key-id"],
+		})
+		return err
+	case PlanDelete:
+		res, err := this.Delete(action.Bucket, action.Key)
+		return this.checkStatus(res, err, "204")
+	case PlanDeleteLocal:
+		err := os.Remove(action.LocalFile)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	case PlanDownload:
+		return this.downloadFile(action.Bucket, action.Key, action.LocalFile, action.Size)
+	case PlanMkdir:
+		return os.MkdirAll(action.LocalFile, 0755)
+	}
+	return errors.New("unsupported plan action: " + action.Action)
+}
+
+// 分段下载时每段的大小
+const downloadPartSize = 16 * 1024 * 1024
+
+// 分段下载到同目录的临时文件后重命名，修改时间设为object的Last-Modified
+//
+// 后续分段带上第一段的ETag作为If-Match，下载过程中object被覆盖或大小与size不一致时放弃下载
+func (this *Client) downloadFile(bucket, object, localfile string, size int64) error {
+	if err := os.MkdirAll(filepath.Dir(localfile), 0755); err != nil {
+		return err
+	}
+	tmpFile := localfile + ".osstmp"
+	fd, err := os.OpenFile(tmpFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	lastModified := ""
+	etag := ""
+	partSize := int64(downloadPartSize)
+	for start := int64(0); start == 0 || start < size; start += partSize {
+		partRange := ""
+		end := start + partSize
+		if end > size {
+			end = size
+		}
+		if size > partSize {
+			partRange = fmt.Sprintf("bytes=%d-%d", start, end-1)
+		}
+		res, err := this.cat(bucket, object, "", map[string]string{"Range": partRange, "If-Match": etag})
+		if err == nil {
+			switch {
+			case res["StatusCode"] == "412":
+				err = errors.New("object changed during download: " + object)
+			case res["StatusCode"] != "200" && res["StatusCode"] != "206":
+				err = this.parseError(res)
+			case partRange != "" && (res["StatusCode"] != "206" || !strings.HasSuffix(res["Content-Range"], "/"+strconv.FormatInt(size, 10)) || int64(len(res["Body"])) != end-start):
+				err = errors.New("object size changed during download: " + object)
+			}
+		}
+		if err == nil {
+			_, err = fd.WriteAt([]byte(res["Body"]), start)
+		}
+		if err != nil {
+			fd.Close()
+			os.Remove(tmpFile)
+			return err
+		}
+		lastModified = res["Last-Modified"]
+		etag = res["Etag"]
+	}
+	if err := fd.Close(); err != nil {
+		os.Remove(tmpFile)
+		return err
+	}
+	if modTime, err := time.Parse(this.dateTimeGMT, lastModified); err == nil {
+		os.Chtimes(tmpFile, modTime, modTime)
+	}
+	if err := os.Rename(tmpFile, localfile); err != nil {
+		os.Remove(tmpFile)
+		return err
+	}
+	return nil
+}
+
+func (this *Client) checkStatus(res map[string]string, err error, statusCode string) error {
+	if err != nil {
+		return err
+	}
+	if res["StatusCode"] != statusCode {
+		return this.parseError(res)
+	}
+	return nil
+}
+
+// UploadFromDir的执行计划：replace不为true时，object已存在、大小相同且不早于本地文件则跳过
+func (this *Client) UploadFromDirPlan(localdir, bucket, prefix string, options map[string]string) (*Plan, error) {
+	localdir = strings.TrimRight(localdir, "/") + "/"
+	objectPrefix := strings.TrimLeft(strings.TrimRight(prefix, "/")+"/", "/")
+	remoteObjects := map[string]ListObjectContents{}
+	if options["replace"] != "true" {
+		var err error
+		if remoteObjects, err = this.listObjectMap(bucket, objectPrefix); err != nil {
+			return nil, err
+		}
+	}
+	filter, err := this.localPathFilter(localdir, options)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{Actions: make([]PlanAction, 0)}
+	for _, fileName := range this.walkdir(localdir, options["suffix"], filter) {
+		fi, err := os.Stat(localdir + fileName)
+		if err != nil {
+			return nil, err
+		}
+		action := PlanAction{
+			Action:    PlanUpload,
+			Bucket:    bucket,
+			Key:       objectPrefix + fileName,
+			LocalFile: localdir + fileName,
+			Size:      fi.Size(),
+			Reason:    "new",
+		}
+		if options["replace"] == "true" {
+			action.Reason = "replace"
+		} else if remote, ok := remoteObjects[action.Key]; ok {
+			action.Action, action.Reason = skipOrReason(PlanUpload, fi.Size(), fi.ModTime(), remote)
+		}
+		plan.add(action)
+	}
+	return plan, nil
+}
+
+// CopyAllObject的执行计划，source为/bucket/prefix；
+// 目标object保留相对源前缀所在目录的路径，flatten为true时只保留文件名，多个源object同名时返回错误；
+// replace不为true时，目标object已存在、大小相同且不早于源object则跳过
+func (this *Client) CopyAllObjectPlan(bucket, prefix, source string, options map[string]string) (*Plan, error) {
+	tmpSourceInfo := strings.Split(source, "/")
+	if len(tmpSourceInfo) < 2 {
+		return nil, errors.New("invalid copy source: " + source)
+	}
+	sourceBucket := tmpSourceInfo[1]
+	sourcePrefix := strings.Join(tmpSourceInfo[2:], "/")
+	objectPrefix := strings.TrimLeft(strings.TrimRight(prefix, "/")+"/", "/")
+	filter, err := NewPathFilter(options)
+	if err != nil {
+		return nil, err
+	}
+	targetObjects := map[string]ListObjectContents{}
+	if options["replace"] != "true" {
+		if targetObjects, err = this.listObjectMap(bucket, objectPrefix); err != nil {
+			return nil, err
+		}
+	}
+	plan := &Plan{Actions: make([]PlanAction, 0)}
+	flattenSources := map[string]string{}
+	done := make(chan struct{})
+	list, listErr := this.ListParallel(sourceBucket, sourcePrefix, this.threadMaxNum, done)
+	for v := range list {
+		relative := relativeKey(v.Key, sourcePrefix)
+		if !filter.Match(relative) {
+			continue
+		}
+		if options["flatten"] == "true" {
+			relative = path.Base(v.Key)
+			if source, ok := flattenSources[relative]; ok {
+				//取消列举，list关闭时后台goroutine已退出
+				close(done)
+				for range list {
+				}
+				return nil, fmt.Errorf("flatten conflict: %s and %s both copy to %s", source, v.Key, objectPrefix+relative)
+			}
+			flattenSources[relative] = v.Key
+		}
+		action := PlanAction{
+			Action: PlanCopy,
+			Bucket: bucket,
+			Key:    objectPrefix + relative,
+			Source: "/" + sourceBucket + "/" + v.Key,
+			Size:   v.SizeInt64(),
+			Reason: "new",
+		}
+		if options["replace"] == "true" {
+			action.Reason = "replace"
+		} else if target, ok := targetObjects[action.Key]; ok {
+			sourceTime, _ := time.Parse("2006-01-02T15:04:05.000Z", v.LastModified)
+			action.Action, action.Reason = skipOrReason(PlanCopy, v.SizeInt64(), sourceTime, target)
+		}
+		plan.add(action)
+	}
+	if err := <-listErr; err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// DeleteAllObject的执行计划，prefix下的所有object
+func (this *Client) DeleteAllObjectPlan(bucket, prefix string, options map[string]string) (*Plan, error) {
+	filter, err := NewPathFilter(options)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{Actions: make([]PlanAction, 0)}
+	list, listErr := this.ListParallel(bucket, prefix, this.threadMaxNum, nil)
+	for v := range list {
+		if !filter.Match(relativeKey(v.Key, prefix)) {
+			continue
+		}
+		plan.add(PlanAction{
+			Action: PlanDelete,
+			Bucket: bucket,
+			Key:    v.Key,
+			Size:   v.SizeInt64(),
+			Reason: "prefix " + prefix,
+		})
+	}
+	if err := <-listErr; err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// 目标已存在时：大小相同且目标不早于源则跳过，否则返回需要执行的原因
+func skipOrReason(action string, sourceSize int64, sourceTime time.Time, target ListObjectContents) (string, string) {
+	if sourceSize != target.SizeInt64() {
+		return action, "size differs"
+	}
+	targetTime, _ := time.Parse("2006-01-02T15:04:05.000Z", target.LastModified)
+	if targetTime.Unix() < sourceTime.Unix() {
+		return action, "source newer"
+	}
+	return PlanSkip, "unchanged"
+}
+
+// 列举prefix下的所有object，按key索引
+func (this *Client) listObjectMap(bucket, prefix string) (map[string]ListObjectContents, error) {
+	objects := map[string]ListObjectContents{}
+	list, listErr := this.ListParallel(bucket, prefix, this.threadMaxNum, nil)
+	for v := range list {
+		objects[v.Key] = v.ListObjectContents
+	}
+	if err := <-listErr; err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// key相对prefix所在目录的路径，用于过滤规则匹配
+func relativeKey(key, prefix string) string {
+	return strings.TrimPrefix(key, prefix[:strings.LastIndex(prefix, "/")+1])
+}
@@ -0,0 +1,178 @@
+package oss
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"lib/aliyun/oss/osstest"
+)
+
+func TestDownloadFileRanges(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	dir, err := ioutil.TempDir("", "ossdownload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	data := bytes.Repeat([]byte("0123456789abcdef"), downloadPartSize/16*2+1)
+	srv.PutObject("bucket", "large", data, nil)
+
+	localfile := filepath.Join(dir, "large")
+	if err := client.downloadFile("bucket", "large", localfile, int64(len(data))); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadFile(localfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("downloaded file differs")
+	}
+	for _, r := range srv.Requests() {
+		if r.Method == "GET" && r.Key == "large" && r.Header.Get("Range") != "bytes=0-16777215" && r.Header.Get("If-Match") == "" {
+			t.Fatalf("range %s sent without If-Match", r.Header.Get("Range"))
+		}
+	}
+
+	//第二段下载前object被覆盖为同样大小的新内容
+	changed := bytes.Repeat([]byte("x"), len(data))
+	gets := 0
+	srv.Hook = func(r *osstest.Request) {
+		if r.Method == "GET" && r.Key == "large" {
+			if gets++; gets == 2 {
+				srv.PutObject("bucket", "large", changed, nil)
+			}
+		}
+	}
+	localfile = filepath.Join(dir, "changed")
+	if err := client.downloadFile("bucket", "large", localfile, int64(len(data))); err == nil {
+		t.Fatal("expected error when object changes during download")
+	}
+	if _, err := os.Stat(localfile); !os.IsNotExist(err) {
+		t.Fatal("partial download renamed into place")
+	}
+	if _, err := os.Stat(localfile + ".osstmp"); !os.IsNotExist(err) {
+		t.Fatal("temp file left behind")
+	}
+
+	//大小与列举结果不一致
+	srv.Hook = nil
+	if err := client.downloadFile("bucket", "large", localfile, int64(len(data))+1); err == nil {
+		t.Fatal("expected error when size differs")
+	}
+}
+
+func TestExecutePlanLargeCopy(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	srv.PutObject("bucket", "small", []byte("small"), nil)
+	srv.PutObject("bucket", "large", []byte("large"), nil)
+
+	//按计划中的Size选择复制方式，这里用Size模拟超过1GB的object
+	plan := &Plan{Actions: []PlanAction{
+		{Action: PlanCopy, Bucket: "bucket", Key: "small-copy", Source: "/bucket/small", Size: 5},
+		{Action: PlanCopy, Bucket: "bucket", Key: "large-copy", Source: "/bucket/large", Size: copyLargeThreshold + 1},
+	}}
+	res, err := client.ExecutePlan(plan, map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res["finish"] != 2 {
+		t.Fatalf("finish = %d, want 2", res["finish"])
+	}
+	multipart := map[string]bool{}
+	for _, r := range srv.Requests() {
+		if r.Method == "POST" && r.Query["uploads"] != nil {
+			multipart[r.Key] = true
+		}
+	}
+	if multipart["small-copy"] || !multipart["large-copy"] {
+		t.Fatalf("multipart copies: %v, want only large-copy", multipart)
+	}
+	if object, ok := srv.Object("bucket", "large-copy"); !ok || string(object.Data) != "large" {
+		t.Fatal("large-copy not copied")
+	}
+}
+
+func TestExecutePlanLargeUpload(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	dir, err := ioutil.TempDir("", "ossupload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	localfile := filepath.Join(dir, "large")
+	if err := ioutil.WriteFile(localfile, []byte("large"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	//用Size模拟超过单次上传阈值的本地文件
+	plan := &Plan{Actions: []PlanAction{
+		{Action: PlanUpload, Bucket: "bucket", Key: "small", LocalFile: localfile, Size: 5},
+		{Action: PlanUpload, Bucket: "bucket", Key: "large", LocalFile: localfile, Size: uploadLargeThreshold + 1},
+	}}
+	res, err := client.ExecutePlan(plan, map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res["finish"] != 2 {
+		t.Fatalf("finish = %d, want 2", res["finish"])
+	}
+	multipart := map[string]bool{}
+	for _, r := range srv.Requests() {
+		if r.Method == "POST" && r.Query["uploads"] != nil {
+			multipart[r.Key] = true
+		}
+	}
+	if multipart["small"] || !multipart["large"] {
+		t.Fatalf("multipart uploads: %v, want only large", multipart)
+	}
+	if object, ok := srv.Object("bucket", "large"); !ok || string(object.Data) != "large" {
+		t.Fatal("large not uploaded")
+	}
+}
+
+func TestBulkOperationsExecutePlan(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	dir, err := ioutil.TempDir("", "ossupload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	threadMaxNum := client.threadMaxNum
+	options := map[string]string{"thread_num": "2"}
+
+	res, err := client.UploadFromDir(dir, "bucket", "src/", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res["total"] != 2 || res["finish"] != 2 {
+		t.Fatalf("UploadFromDir = %v", res)
+	}
+	res, err = client.CopyAllObject("bucket", "dst/", "/bucket/src/", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res["total"] != 2 || res["finish"] != 2 {
+		t.Fatalf("CopyAllObject = %v", res)
+	}
+	for _, key := range []string{"dst/a.txt", "dst/b.txt"} {
+		if _, ok := srv.Object("bucket", key); !ok {
+			t.Fatalf("%s not copied", key)
+		}
+	}
+	if client.threadMaxNum != threadMaxNum {
+		t.Fatalf("threadMaxNum changed to %d", client.threadMaxNum)
+	}
+}
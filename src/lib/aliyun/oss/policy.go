@@ -0,0 +1,87 @@
+package oss
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// 只用于校验结构，上传时使用原始JSON
+type bucketPolicyDocument struct {
+	Version   string                  `json:"Version"`
+	Statement []bucketPolicyStatement `json:"Statement"`
+}
+
+type bucketPolicyStatement struct {
+	Effect    string          `json:"Effect"`
+	Action    json.RawMessage `json:"Action"`
+	Principal json.RawMessage `json:"Principal"`
+	Resource  json.RawMessage `json:"Resource"`
+	Condition json.RawMessage `json:"Condition"`
+}
+
+func ValidateBucketPolicy(policy string) error {
+	var document bucketPolicyDocument
+	if err := json.Unmarshal([]byte(policy), &document); err != nil {
+		return errors.New("policy: invalid JSON: " + err.Error())
+	}
+	if document.Version == "" {
+		return errors.New("policy: Version is required")
+	}
+	if len(document.Statement) == 0 {
+		return errors.New("policy: Statement is required")
+	}
+	for i, statement := range document.Statement {
+		name := "policy statement #" + strconv.Itoa(i+1)
+		if statement.Effect != "Allow" && statement.Effect != "Deny" {
+			return errors.New(name + ": Effect must be Allow or Deny")
+		}
+		if !isStringOrStringList(statement.Action) {
+			return errors.New(name + ": Action must be a string or a non-empty string list")
+		}
+		if !isStringOrStringList(statement.Resource) {
+			return errors.New(name + ": Resource must be a string or a non-empty string list")
+		}
+		if len(statement.Principal) > 0 && !isStringOrStringList(statement.Principal) {
+			return errors.New(name + ": Principal must be a string or a string list")
+		}
+		if len(statement.Condition) > 0 {
+			var condition map[string]map[string]interface{}
+			if err := json.Unmarshal(statement.Condition, &condition); err != nil {
+				return errors.New(name + ": Condition must be an object of operator to key/values")
+			}
+		}
+	}
+	return nil
+}
+
+func isStringOrStringList(data json.RawMessage) bool {
+	var tmpString string
+	if err := json.Unmarshal(data, &tmpString); err == nil {
+		return tmpString != ""
+	}
+	var tmpList []string
+	if err := json.Unmarshal(data, &tmpList); err == nil {
+		return len(tmpList) > 0
+	}
+	return false
+}
+
+func (this *Client) GetBucketPolicy(bucket string) (string, error) {
+	res, err := this.bucketRequest("GET", bucket, "?policy", "", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	return res["Body"], nil
+}
+
+func (this *Client) PutBucketPolicy(bucket, policy string) (map[string]string, error) {
+	if err := ValidateBucketPolicy(policy); err != nil {
+		return nil, err
+	}
+	return this.bucketRequest("PUT", bucket, "?policy", "", []byte(policy), map[string]string{"Content-Type": "application/json"})
+}
+
+func (this *Client) DeleteBucketPolicy(bucket string) (map[string]string, error) {
+	return this.bucketRequest("DELETE", bucket, "?policy", "", nil, nil)
+}
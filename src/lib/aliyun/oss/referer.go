@@ -0,0 +1,48 @@
+package oss
+
+import (
+	"encoding/xml"
+)
+
+type RefererConfiguration struct {
+	XMLName                  xml.Name `xml:"RefererConfiguration" json:"-"`
+	AllowEmptyReferer        bool     `xml:"AllowEmptyReferer" json:"AllowEmptyReferer"`
+	AllowTruncateQueryString *bool    `xml:"AllowTruncateQueryString,omitempty" json:"AllowTruncateQueryString,omitempty"`
+	RefererList              []string `xml:"RefererList>Referer" json:"RefererList"`
+	RefererBlacklist         []string `xml:"RefererBlacklist>Referer,omitempty" json:"RefererBlacklist,omitempty"`
+}
+
+func (this *Client) GetBucketReferer(bucket string) (*RefererConfiguration, error) {
+	res, err := this.bucketRequest("GET", bucket, "?referer", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var referer RefererConfiguration
+	if err := xml.Unmarshal([]byte(res["Body"]), &referer); err != nil {
+		return nil, err
+	}
+	return &referer, nil
+}
+
+func (this *Client) PutBucketReferer(bucket string, referer *RefererConfiguration) (map[string]string, error) {
+	body, err := refererBody(referer)
+	if err != nil {
+		return nil, err
+	}
+	return this.bucketRequest("PUT", bucket, "?referer", "", body, nil)
+}
+
+// RefererList为空时xml不输出该元素，OSS会返回MalformedXML，同官方SDK发送一个空的Referer
+func refererBody(referer *RefererConfiguration) ([]byte, error) {
+	if len(referer.RefererList) == 0 {
+		tmp := *referer
+		tmp.RefererList = []string{""}
+		referer = &tmp
+	}
+	return xml.Marshal(referer)
+}
+
+// OSS没有删除防盗链的接口，恢复为允许空Referer且白名单为空的默认配置
+func (this *Client) DeleteBucketReferer(bucket string) (map[string]string, error) {
+	return this.PutBucketReferer(bucket, &RefererConfiguration{AllowEmptyReferer: true})
+}
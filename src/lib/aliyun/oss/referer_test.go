@@ -0,0 +1,28 @@
+package oss
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRefererBodyEmptyList(t *testing.T) {
+	referer := &RefererConfiguration{AllowEmptyReferer: true}
+	body, err := refererBody(referer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "<RefererList><Referer></Referer></RefererList>") {
+		t.Fatalf("RefererList missing: %s", body)
+	}
+	if referer.RefererList != nil {
+		t.Fatal("refererBody modified the configuration")
+	}
+
+	body, err = refererBody(&RefererConfiguration{RefererList: []string{"http://www.aliyun.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "<RefererList><Referer>http://www.aliyun.com</Referer></RefererList>") {
+		t.Fatalf("unexpected body: %s", body)
+	}
+}
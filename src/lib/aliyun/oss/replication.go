@@ -0,0 +1,111 @@
+package oss
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/url"
+)
+
+type ReplicationConfiguration struct {
+	XMLName xml.Name          `xml:"ReplicationConfiguration" json:"-"`
+	Rules   []ReplicationRule `xml:"Rule" json:"Rules"`
+}
+
+type ReplicationRule struct {
+	ID                          string                      `xml:"ID,omitempty" json:"ID,omitempty"`
+	Prefixes                    []string                    `xml:"PrefixSet>Prefix,omitempty" json:"Prefixes,omitempty"`
+	Action                      string                      `xml:"Action,omitempty" json:"Action,omitempty"`
+	Destination                 ReplicationDestination      `xml:"Destination" json:"Destination"`
+	Status                      string                      `xml:"Status,omitempty" json:"Status,omitempty"`
+	HistoricalObjectReplication string                      `xml:"HistoricalObjectReplication,omitempty" json:"HistoricalObjectReplication,omitempty"`
+	SyncRole                    string                      `xml:"SyncRole,omitempty" json:"SyncRole,omitempty"`
+	SseKmsEncryptedObjects      string                      `xml:"SourceSelectionCriteria>SseKmsEncryptedObjects>Status,omitempty" json:"SseKmsEncryptedObjects,omitempty"`
+	ReplicaKmsKeyID             string                      `xml:"EncryptionConfiguration>ReplicaKmsKeyID,omitempty" json:"ReplicaKmsKeyID,omitempty"`
+	Progress                    *ReplicationProgressDetails `xml:"Progress,omitempty" json:"Progress,omitempty"`
+}
+
+type ReplicationDestination struct {
+	Bucket       string `xml:"Bucket" json:"Bucket"`
+	Location     string `xml:"Location" json:"Location"`
+	TransferType string `xml:"TransferType,omitempty" json:"TransferType,omitempty"`
+}
+
+// HistoricalObject为历史数据复制进度(0~1)，NewObject为已复制到的新写入数据时间点
+type ReplicationProgressDetails struct {
+	HistoricalObject string `xml:"HistoricalObject,omitempty" json:"HistoricalObject,omitempty"`
+	NewObject        string `xml:"NewObject,omitempty" json:"NewObject,omitempty"`
+}
+
+type ReplicationProgress struct {
+	XMLName xml.Name          `xml:"ReplicationProgress" json:"-"`
+	Rules   []ReplicationRule `xml:"Rule" json:"Rules"`
+}
+
+type replicationRules struct {
+	XMLName xml.Name `xml:"ReplicationRules"`
+	IDs     []string `xml:"ID"`
+}
+
+func (this *Client) GetBucketReplication(bucket string) (*ReplicationConfiguration, error) {
+	res, err := this.bucketRequest("GET", bucket, "?replication", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var replication ReplicationConfiguration
+	if err := xml.Unmarshal([]byte(res["Body"]), &replication); err != nil {
+		return nil, err
+	}
+	return &replication, nil
+}
+
+// 每次请求只能添加一条规则，多条规则逐条提交
+func (this *Client) PutBucketReplication(bucket string, replication *ReplicationConfiguration) (map[string]string, error) {
+	if len(replication.Rules) == 0 {
+		return nil, errors.New("replication: no rules")
+	}
+	for _, rule := range replication.Rules {
+		if rule.Destination.Bucket == "" || rule.Destination.Location == "" {
+			return nil, errors.New("replication rule: Destination Bucket and Location are required")
+		}
+	}
+	var res map[string]string
+	for _, rule := range replication.Rules {
+		//只读字段不能提交
+		rule.Status = ""
+		rule.Progress = nil
+		body, err := xml.Marshal(&ReplicationConfiguration{Rules: []ReplicationRule{rule}})
+		if err != nil {
+			return nil, err
+		}
+		res, err = this.bucketRequest("POST", bucket, "?comp=add&replication", "", body, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (this *Client) DeleteBucketReplication(bucket, ruleId string) (map[string]string, error) {
+	body, err := xml.Marshal(&replicationRules{IDs: []string{ruleId}})
+	if err != nil {
+		return nil, err
+	}
+	return this.bucketRequest("POST", bucket, "?comp=delete&replication", "", body, nil)
+}
+
+// ruleId为空时返回所有规则的进度
+func (this *Client) GetBucketReplicationProgress(bucket, ruleId string) (*ReplicationProgress, error) {
+	query := ""
+	if ruleId != "" {
+		query = "rule-id=" + url.QueryEscape(ruleId)
+	}
+	res, err := this.bucketRequest("GET", bucket, "?replicationProgress", query, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var progress ReplicationProgress
+	if err := xml.Unmarshal([]byte(res["Body"]), &progress); err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
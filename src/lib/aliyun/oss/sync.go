@@ -0,0 +1,254 @@
+package oss
+
+import (
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc64"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	SyncUpload   = "upload"
+	SyncDownload = "download"
+)
+
+type syncFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// 同步本地目录和bucket前缀，先计算执行计划再执行，返回执行计划和执行结果
+func (this *Client) Sync(localdir, bucket, prefix, direction string, options map[string]string) (*Plan, map[string]int, error) {
+	plan, err := this.SyncPlan(localdir, bucket, prefix, direction, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := this.ExecutePlan(plan, options)
+	if err != nil {
+		return plan, nil, err
+	}
+	return plan, res, nil
+}
+
+// 计算同步需要的上传、下载和删除操作，不做任何修改
+//
+// direction为SyncUpload(本地到OSS)或SyncDownload(OSS到本地)，options：
+//
+//	delete     为true时删除目标端多余的object或文件
+//	compare    size、mtime(默认，大小相同且目标不早于源时跳过)、etag、checksum(CRC64)
+//	suffix     后缀过滤，同UploadFromDir，同时用于本地文件和object
+//	include、exclude等过滤规则同NewPathFilter，同时用于本地文件和object
+//	thread_num 列举和比较的并发数
+func (this *Client) SyncPlan(localdir, bucket, prefix, direction string, options map[string]string) (*Plan, error) {
+	if direction != SyncUpload && direction != SyncDownload {
+		return nil, errors.New("unsupported sync direction: " + direction)
+	}
+	compare := options["compare"]
+	if compare == "" {
+		compare = "mtime"
+	}
+	if compare != "size" && compare != "mtime" && compare != "etag" && compare != "checksum" {
+		return nil, errors.New("unsupported sync compare: " + compare)
+	}
+	threadNum := this.threadMaxNum
+	if options["thread_num"] != "" {
+		tmpThreadNum, err := strconv.Atoi(options["thread_num"])
+		if err == nil && tmpThreadNum <= this.threadMaxNum && tmpThreadNum >= this.threadMinNum {
+			threadNum = tmpThreadNum
+		}
+	}
+	localdir = strings.TrimRight(localdir, "/") + "/"
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	filter, err := this.localPathFilter(localdir, options)
+	if err != nil {
+		return nil, err
+	}
+	localFiles := map[string]syncFile{}
+	if _, err := os.Stat(localdir); err == nil {
+		for _, fileName := range this.walkdir(localdir, options["suffix"], filter) {
+			if strings.HasSuffix(fileName, ".osstmp") {
+				continue
+			}
+			fi, err := os.Stat(localdir + fileName)
+			if err != nil {
+				return nil, err
+			}
+			localFiles[fileName] = syncFile{path: localdir + fileName, size: fi.Size(), modTime: fi.ModTime()}
+		}
+	} else if direction == SyncUpload {
+		return nil, err
+	}
+
+	remoteObjects := map[string]ListObjectContents{}
+	list, listErr := this.ListParallel(bucket, prefix, threadNum, nil)
+	for v := range list {
+		//跳过目录占位object和被过滤(包括suffix不匹配)的object，被过滤的object也不会被删除
+		name := strings.TrimPrefix(v.Key, prefix)
+		if strings.HasSuffix(v.Key, "/") || !matchSuffix(name, options["suffix"]) || !filter.Match(name) {
+			continue
+		}
+		remoteObjects[name] = v.ListObjectContents
+	}
+	if err := <-listErr; err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(localFiles)+len(remoteObjects))
+	for name := range localFiles {
+		names = append(names, name)
+	}
+	for name := range remoteObjects {
+		if _, ok := localFiles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	actions := make([]PlanAction, len(names))
+	var wg sync.WaitGroup
+	var queueMaxSize = make(chan bool, threadNum)
+	var compareErr error
+	var compareErrOnce sync.Once
+	for i, name := range names {
+		local, hasLocal := localFiles[name]
+		remote, hasRemote := remoteObjects[name]
+		action := PlanAction{Bucket: bucket, Key: prefix + name, LocalFile: localdir + name}
+		if direction == SyncUpload {
+			action.Size = local.size
+		} else {
+			action.Size = remote.SizeInt64()
+		}
+		switch {
+		case direction == SyncUpload && !hasRemote:
+			action.Action, action.Reason = PlanUpload, "new"
+		case direction == SyncUpload && !hasLocal:
+			action.Action, action.Reason, action.LocalFile = PlanDelete, "not in source", ""
+			action.Size = remote.SizeInt64()
+		case direction == SyncDownload && !isSafeLocalPath(name):
+			action.Action, action.Reason, action.LocalFile = PlanSkip, "unsafe key", ""
+		case direction == SyncDownload && !hasLocal:
+			action.Action, action.Reason = PlanDownload, "new"
+		case direction == SyncDownload && !hasRemote:
+			action.Action, action.Reason, action.Key = PlanDeleteLocal, "not in source", ""
+			action.Size = local.size
+		default:
+			//两端都存在，并发比较
+			wg.Add(1)
+			queueMaxSize <- true
+			go func(i int, action PlanAction) {
+				defer wg.Done()
+				defer func() { <-queueMaxSize }()
+				reason, err := this.syncCompare(compare, direction, local, remote, bucket, action.Key)
+				if err != nil {
+					compareErrOnce.Do(func() { compareErr = err })
+					return
+				}
+				action.Action, action.Reason = direction, reason
+				if reason == "" {
+					action.Action, action.Reason = PlanSkip, "unchanged"
+				}
+				actions[i] = action
+			}(i, action)
+			continue
+		}
+		actions[i] = action
+	}
+	wg.Wait()
+	if compareErr != nil {
+		return nil, compareErr
+	}
+
+	plan := &Plan{Actions: make([]PlanAction, 0, len(actions))}
+	for _, action := range actions {
+		if (action.Action == PlanDelete || action.Action == PlanDeleteLocal) && options["delete"] != "true" {
+			continue
+		}
+		plan.add(action)
+	}
+	return plan, nil
+}
+
+// 返回需要同步的原因，不需要同步时返回空
+func (this *Client) syncCompare(compare, direction string, local syncFile, remote ListObjectContents, bucket, object string) (string, error) {
+	if local.size != remote.SizeInt64() {
+		return "size differs", nil
+	}
+	switch compare {
+	case "mtime":
+		remoteTime, err := time.Parse("2006-01-02T15:04:05.000Z", remote.LastModified)
+		if err != nil {
+			return "", err
+		}
+		if direction == SyncUpload && remoteTime.Unix() < local.modTime.Unix() {
+			return "source newer", nil
+		}
+		if direction == SyncDownload && local.modTime.Unix() < remoteTime.Unix() {
+			return "source newer", nil
+		}
+	case "etag":
+		etag := strings.ToUpper(strings.Trim(remote.ETag, `"`))
+		//分片上传的ETag不是内容的MD5，改为比较CRC64
+		if !strings.Contains(etag, "-") {
+			sum, err := fileHash(local.path, md5.New())
+			if err != nil {
+				return "", err
+			}
+			if strings.ToUpper(sum) != etag {
+				return "etag differs", nil
+			}
+			return "", nil
+		}
+		fallthrough
+	case "checksum":
+		objectHead, err := this.Head(bucket, object)
+		if err != nil {
+			return "", err
+		}
+		if objectHead["StatusCode"] != "200" {
+			return "", errors.New("StatusCode:" + objectHead["StatusCode"])
+		}
+		sum, err := fileHash(local.path, crc64.New(crc64.MakeTable(crc64.ECMA)))
+		if err != nil {
+			return "", err
+		}
+		crc, _ := strconv.ParseUint(sum, 16, 64)
+		if strconv.FormatUint(crc, 10) != objectHead["X-Oss-Hash-Crc64ecma"] {
+			return "checksum differs", nil
+		}
+	}
+	return "", nil
+}
+
+func fileHash(localfile string, h hash.Hash) (string, error) {
+	fd, err := os.Open(localfile)
+	if err != nil {
+		return "", err
+	}
+	defer fd.Close()
+	if _, err := io.Copy(h, fd); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// key作为本地相对路径时不能是绝对路径或跳出目标目录
+func isSafeLocalPath(name string) bool {
+	if name == "" || strings.HasPrefix(name, "/") || filepath.IsAbs(name) {
+		return false
+	}
+	clean := filepath.ToSlash(filepath.Clean(name))
+	return clean != ".." && !strings.HasPrefix(clean, "../")
+}
@@ -0,0 +1,55 @@
+package oss
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSyncPlanSuffixWithDelete(t *testing.T) {
+	client, srv := newTestClient()
+	defer srv.Close()
+	localdir, err := ioutil.TempDir("", "osssync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(localdir)
+	for _, name := range []string{"a.jpg", "b.txt"} {
+		if err := ioutil.WriteFile(filepath.Join(localdir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv.PutObject("bucket", "p/c.txt", []byte("c"), nil)
+	srv.PutObject("bucket", "p/d.JPG", []byte("d"), nil)
+
+	plan, err := client.SyncPlan(localdir, "bucket", "p", SyncUpload, map[string]string{"suffix": ".jpg", "delete": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := planActions(plan)
+	expected := map[string]string{"p/a.jpg": PlanUpload, "p/d.JPG": PlanDelete}
+	if len(actions) != len(expected) {
+		t.Fatalf("actions = %v, want %v", actions, expected)
+	}
+	for k, v := range expected {
+		if actions[k] != v {
+			t.Errorf("action of %s = %q, want %q", k, actions[k], v)
+		}
+	}
+
+	plan, err = client.SyncPlan(localdir, "bucket", "p", SyncDownload, map[string]string{"suffix": ".jpg", "delete": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions = planActions(plan)
+	expected = map[string]string{"p/d.JPG": PlanDownload, filepath.Join(localdir, "a.jpg"): PlanDeleteLocal}
+	if len(actions) != len(expected) {
+		t.Fatalf("actions = %v, want %v", actions, expected)
+	}
+	for k, v := range expected {
+		if actions[k] != v {
+			t.Errorf("action of %s = %q, want %q", k, actions[k], v)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package oss
+
+import (
+	"encoding/xml"
+	"errors"
+)
+
+type Tagging struct {
+	XMLName xml.Name `xml:"Tagging" json:"-"`
+	Tags    []Tag    `xml:"TagSet>Tag" json:"Tags"`
+}
+
+func (this *Client) GetBucketTagging(bucket string) (*Tagging, error) {
+	res, err := this.bucketRequest("GET", bucket, "?tagging", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var tagging Tagging
+	if err := xml.Unmarshal([]byte(res["Body"]), &tagging); err != nil {
+		return nil, err
+	}
+	return &tagging, nil
+}
+
+func (this *Tagging) Validate() error {
+	if len(this.Tags) > 20 {
+		return errors.New("tagging: at most 20 tags")
+	}
+	for _, tag := range this.Tags {
+		if tag.Key == "" {
+			return errors.New("tagging: Key is required")
+		}
+	}
+	return nil
+}
+
+func (this *Client) PutBucketTagging(bucket string, tagging *Tagging) (map[string]string, error) {
+	if err := tagging.Validate(); err != nil {
+		return nil, err
+	}
+	body, err := xml.Marshal(tagging)
+	if err != nil {
+		return nil, err
+	}
+	return this.bucketRequest("PUT", bucket, "?tagging", "", body, nil)
+}
+
+func (this *Client) DeleteBucketTagging(bucket string) (map[string]string, error) {
+	return this.bucketRequest("DELETE", bucket, "?tagging", "", nil, nil)
+}
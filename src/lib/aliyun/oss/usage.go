@@ -0,0 +1,76 @@
+package oss
+
+import (
+	"sort"
+)
+
+// 目录(公共前缀)的object数量和大小，包含所有子目录
+type DirUsage struct {
+	Prefix       string
+	Count        int64
+	Size         int64
+	StorageClass map[string]*StorageClassUsage
+	Dirs         []*DirUsage
+}
+
+type StorageClassUsage struct {
+	Count int64
+	Size  int64
+}
+
+// 统计prefix下各目录的object数量和大小，按存储类型分别汇总
+//
+// depth为展开的子目录层数：在depth层以内用delimiter="/"逐层列举，
+// 超过depth的目录不再拆分，直接完整列举后计入上层目录
+func (this *Client) DiskUsage(bucket, prefix string, depth int) (*DirUsage, error) {
+	usage := &DirUsage{
+		Prefix:       prefix,
+		StorageClass: map[string]*StorageClassUsage{},
+		Dirs:         make([]*DirUsage, 0),
+	}
+	options := map[string]string{"prefix": prefix}
+	if depth > 0 {
+		options["delimiter"] = "/"
+	}
+	it := this.NewObjectIterator(bucket, options)
+	defer it.Close()
+	for it.Next() {
+		v := it.Object()
+		if !v.IsPrefix {
+			usage.add(v.StorageClass, 1, v.SizeInt64())
+			continue
+		}
+		dir, err := this.DiskUsage(bucket, v.Key, depth-1)
+		if err != nil {
+			return nil, err
+		}
+		usage.Dirs = append(usage.Dirs, dir)
+		for class, classUsage := range dir.StorageClass {
+			usage.add(class, classUsage.Count, classUsage.Size)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+func (this *DirUsage) add(storageClass string, count, size int64) {
+	if this.StorageClass[storageClass] == nil {
+		this.StorageClass[storageClass] = &StorageClassUsage{}
+	}
+	this.StorageClass[storageClass].Count += count
+	this.StorageClass[storageClass].Size += size
+	this.Count += count
+	this.Size += size
+}
+
+// 按名称排序的存储类型
+func (this *DirUsage) StorageClasses() []string {
+	classes := make([]string, 0, len(this.StorageClass))
+	for class := range this.StorageClass {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	return classes
+}
@@ -0,0 +1,36 @@
+package oss
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBucketConfigValidate(t *testing.T) {
+	tags := make([]Tag, 21)
+	for i := range tags {
+		tags[i].Key = fmt.Sprintf("k%d", i)
+	}
+	cases := []struct {
+		name string
+		err  error
+		ok   bool
+	}{
+		{"acl", ValidateBucketACL("public-read"), true},
+		{"acl invalid", ValidateBucketACL("public"), false},
+		{"versioning", (&VersioningConfiguration{Status: "Enabled"}).Validate(), true},
+		{"versioning empty", (&VersioningConfiguration{}).Validate(), false},
+		{"logging disabled", (&BucketLoggingStatus{}).Validate(), true},
+		{"logging no target", (&BucketLoggingStatus{LoggingEnabled: &BucketLoggingEnabled{}}).Validate(), false},
+		{"encryption", (&ServerSideEncryptionRule{ApplyServerSideEncryptionByDefault: ApplyServerSideEncryptionByDefault{SSEAlgorithm: "KMS", KMSMasterKeyID: "id"}}).Validate(), true},
+		{"encryption key id", (&ServerSideEncryptionRule{ApplyServerSideEncryptionByDefault: ApplyServerSideEncryptionByDefault{SSEAlgorithm: "AES256", KMSMasterKeyID: "id"}}).Validate(), false},
+		{"encryption algorithm", (&ServerSideEncryptionRule{ApplyServerSideEncryptionByDefault: ApplyServerSideEncryptionByDefault{SSEAlgorithm: "DES"}}).Validate(), false},
+		{"tagging", (&Tagging{Tags: tags[:20]}).Validate(), true},
+		{"tagging too many", (&Tagging{Tags: tags}).Validate(), false},
+		{"tagging empty key", (&Tagging{Tags: []Tag{{Value: "v"}}}).Validate(), false},
+	}
+	for _, c := range cases {
+		if (c.err == nil) != c.ok {
+			t.Fatalf("%s: err = %v", c.name, c.err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package oss
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"time"
+)
+
+type ListObjectVersionsResult struct {
+	Name                string               `xml:"Name"`
+	Prefix              string               `xml:"Prefix"`
+	KeyMarker           string               `xml:"KeyMarker"`
+	VersionIdMarker     string               `xml:"VersionIdMarker"`
+	NextKeyMarker       string               `xml:"NextKeyMarker"`
+	NextVersionIdMarker string               `xml:"NextVersionIdMarker"`
+	MaxKeys             string               `xml:"MaxKeys"`
+	Delimiter           string               `xml:"Delimiter"`
+	IsTruncated         string               `xml:"IsTruncated"`
+	Versions            []ObjectVersion      `xml:"Version"`
+	DeleteMarkers       []ObjectDeleteMarker `xml:"DeleteMarker"`
+	CommonPrefixes      []ListCommonPrefix   `xml:"CommonPrefixes"`
+}
+
+type ObjectVersion struct {
+	Key          string      `xml:"Key"`
+	VersionId    string      `xml:"VersionId"`
+	IsLatest     string      `xml:"IsLatest"`
+	LastModified string      `xml:"LastModified"`
+	ETag         string      `xml:"ETag"`
+	Type         string      `xml:"Type"`
+	Size         string      `xml:"Size"`
+	StorageClass string      `xml:"StorageClass"`
+	Owner        ObjectOwner `xml:"Owner"`
+}
+
+type ObjectDeleteMarker struct {
+	Key          string      `xml:"Key"`
+	VersionId    string      `xml:"VersionId"`
+	IsLatest     string      `xml:"IsLatest"`
+	LastModified string      `xml:"LastModified"`
+	Owner        ObjectOwner `xml:"Owner"`
+}
+
+type ObjectOwner struct {
+	ID          string `xml:"ID"`
+	DisplayName string `xml:"DisplayName"`
+}
+
+type ListCommonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// 分页使用NextKeyMarker/NextVersionIdMarker作为下次请求的key-marker/version-id-marker
+func (this *Client) ListObjectVersions(bucket string, options map[string]string) (*ListObjectVersionsResult, error) {
+	param := ""
+	for _, k := range []string{"delimiter", "key-marker", "version-id-marker", "max-keys", "prefix"} {
+		if options[k] != "" {
+			param += "&" + k + "=" + url.QueryEscape(options[k])
+		}
+	}
+	addr := "http://" + bucket + this.host + "/?versions" + param
+	method := "GET"
+	date := time.Unix(time.Now().Unix()-8*3600, 0).Format(this.dateTimeGMT)
+	headers := map[string]string{
+		"Date": date,
+	}
+	LF := "\n"
+	headers["Authorization"] = this.sign(method+LF+LF, headers, bucket, "?versions")
+	res, err := this.curl(addr, method, headers, []byte(""))
+	if err != nil {
+		return nil, err
+	}
+	if res["StatusCode"] != "200" {
+		return nil, this.parseError(res)
+	}
+	var listVersions ListObjectVersionsResult
+	if err := xml.Unmarshal([]byte(res["Body"]), &listVersions); err != nil {
+		return nil, err
+	}
+	return &listVersions, nil
+}
+
+type VersioningConfiguration struct {
+	XMLName xml.Name `xml:"VersioningConfiguration" json:"-"`
+	Status  string   `xml:"Status,omitempty" json:"Status,omitempty"`
+}
+
+// 从未开启过版本控制的bucket，Status为空
+func (this *Client) GetBucketVersioning(bucket string) (*VersioningConfiguration, error) {
+	res, err := this.bucketRequest("GET", bucket, "?versioning", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var versioning VersioningConfiguration
+	if err := xml.Unmarshal([]byte(res["Body"]), &versioning); err != nil {
+		return nil, err
+	}
+	return &versioning, nil
+}
+
+// status: Enabled、Suspended
+func (this *VersioningConfiguration) Validate() error {
+	if this.Status != "Enabled" && this.Status != "Suspended" {
+		return errors.New("versioning: Status must be Enabled or Suspended")
+	}
+	return nil
+}
+
+func (this *Client) PutBucketVersioning(bucket string, versioning *VersioningConfiguration) (map[string]string, error) {
+	if err := versioning.Validate(); err != nil {
+		return nil, err
+	}
+	body, err := xml.Marshal(versioning)
+	if err != nil {
+		return nil, err
+	}
+	return this.bucketRequest("PUT", bucket, "?versioning", "", body, nil)
+}
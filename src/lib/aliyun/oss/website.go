@@ -0,0 +1,93 @@
+package oss
+
+import (
+	"encoding/xml"
+	"errors"
+)
+
+type WebsiteConfiguration struct {
+	XMLName       xml.Name              `xml:"WebsiteConfiguration" json:"-"`
+	IndexDocument *WebsiteIndexDocument `xml:"IndexDocument,omitempty" json:"IndexDocument,omitempty"`
+	ErrorDocument *WebsiteErrorDocument `xml:"ErrorDocument,omitempty" json:"ErrorDocument,omitempty"`
+	RoutingRules  []WebsiteRoutingRule  `xml:"RoutingRules>RoutingRule,omitempty" json:"RoutingRules,omitempty"`
+}
+
+type WebsiteIndexDocument struct {
+	Suffix        string `xml:"Suffix" json:"Suffix"`
+	SupportSubDir *bool  `xml:"SupportSubDir,omitempty" json:"SupportSubDir,omitempty"`
+	Type          string `xml:"Type,omitempty" json:"Type,omitempty"`
+}
+
+type WebsiteErrorDocument struct {
+	Key        string `xml:"Key" json:"Key"`
+	HttpStatus string `xml:"HttpStatus,omitempty" json:"HttpStatus,omitempty"`
+}
+
+type WebsiteRoutingRule struct {
+	RuleNumber int              `xml:"RuleNumber" json:"RuleNumber"`
+	Condition  WebsiteCondition `xml:"Condition" json:"Condition"`
+	Redirect   WebsiteRedirect  `xml:"Redirect" json:"Redirect"`
+}
+
+type WebsiteCondition struct {
+	KeyPrefixEquals             string `xml:"KeyPrefixEquals,omitempty" json:"KeyPrefixEquals,omitempty"`
+	KeySuffixEquals             string `xml:"KeySuffixEquals,omitempty" json:"KeySuffixEquals,omitempty"`
+	HttpErrorCodeReturnedEquals string `xml:"HttpErrorCodeReturnedEquals,omitempty" json:"HttpErrorCodeReturnedEquals,omitempty"`
+}
+
+type WebsiteRedirect struct {
+	RedirectType         string `xml:"RedirectType" json:"RedirectType"`
+	Protocol             string `xml:"Protocol,omitempty" json:"Protocol,omitempty"`
+	HostName             string `xml:"HostName,omitempty" json:"HostName,omitempty"`
+	HttpRedirectCode     string `xml:"HttpRedirectCode,omitempty" json:"HttpRedirectCode,omitempty"`
+	ReplaceKeyPrefixWith string `xml:"ReplaceKeyPrefixWith,omitempty" json:"ReplaceKeyPrefixWith,omitempty"`
+	ReplaceKeyWith       string `xml:"ReplaceKeyWith,omitempty" json:"ReplaceKeyWith,omitempty"`
+	PassQueryString      *bool  `xml:"PassQueryString,omitempty" json:"PassQueryString,omitempty"`
+	MirrorURL            string `xml:"MirrorURL,omitempty" json:"MirrorURL,omitempty"`
+	EnableReplacePrefix  *bool  `xml:"EnableReplacePrefix,omitempty" json:"EnableReplacePrefix,omitempty"`
+}
+
+func (this *WebsiteConfiguration) Validate() error {
+	if this.IndexDocument == nil && this.ErrorDocument == nil && len(this.RoutingRules) == 0 {
+		return errors.New("website: IndexDocument, ErrorDocument or RoutingRules is required")
+	}
+	if this.IndexDocument != nil && this.IndexDocument.Suffix == "" {
+		return errors.New("website: IndexDocument Suffix is required")
+	}
+	if this.ErrorDocument != nil && this.ErrorDocument.Key == "" {
+		return errors.New("website: ErrorDocument Key is required")
+	}
+	for _, rule := range this.RoutingRules {
+		if rule.RuleNumber <= 0 || rule.Redirect.RedirectType == "" {
+			return errors.New("website routing rule: RuleNumber and Redirect RedirectType are required")
+		}
+	}
+	return nil
+}
+
+func (this *Client) GetBucketWebsite(bucket string) (*WebsiteConfiguration, error) {
+	res, err := this.bucketRequest("GET", bucket, "?website", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var website WebsiteConfiguration
+	if err := xml.Unmarshal([]byte(res["Body"]), &website); err != nil {
+		return nil, err
+	}
+	return &website, nil
+}
+
+func (this *Client) PutBucketWebsite(bucket string, website *WebsiteConfiguration) (map[string]string, error) {
+	if err := website.Validate(); err != nil {
+		return nil, err
+	}
+	body, err := xml.Marshal(website)
+	if err != nil {
+		return nil, err
+	}
+	return this.bucketRequest("PUT", bucket, "?website", "", body, nil)
+}
+
+func (this *Client) DeleteBucketWebsite(bucket string) (map[string]string, error) {
+	return this.bucketRequest("DELETE", bucket, "?website", "", nil, nil)
+}